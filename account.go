@@ -1,6 +1,6 @@
 package mt5client
 
-import "fmt"
+import "context"
 
 // AccountService -
 type AccountService struct {
@@ -9,16 +9,25 @@ type AccountService struct {
 
 // GetInfo ดึงข้อมูลบัญชีทั้งหมด
 func (r *AccountService) GetInfo() (*Account, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetInfoCtx(context.Background())
+}
+
+// GetInfoCtx ดึงข้อมูลบัญชีทั้งหมด (รองรับ context)
+func (r *AccountService) GetInfoCtx(ctx context.Context) (*Account, error) {
+	ctx, span := r.client.startSpan(ctx, "AccountService.GetInfo")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var account Account
-	err := r.client.get("/Account", queryParams, &account)
+	err := r.client.get(ctx, "/Account", queryParams, &account)
 	if err != nil {
 		return nil, err
 	}
@@ -28,16 +37,25 @@ func (r *AccountService) GetInfo() (*Account, error) {
 
 // GetDetails ดึงรายละเอียดบัญชี
 func (r *AccountService) GetDetails() (*Account, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetDetailsCtx(context.Background())
+}
+
+// GetDetailsCtx ดึงรายละเอียดบัญชี (รองรับ context)
+func (r *AccountService) GetDetailsCtx(ctx context.Context) (*Account, error) {
+	ctx, span := r.client.startSpan(ctx, "AccountService.GetDetails")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var account Account
-	err := r.client.get("/AccountDetails", queryParams, &account)
+	err := r.client.get(ctx, "/AccountDetails", queryParams, &account)
 	if err != nil {
 		return nil, err
 	}
@@ -47,16 +65,25 @@ func (r *AccountService) GetDetails() (*Account, error) {
 
 // GetSummary ดึงสรุปบัญชี
 func (r *AccountService) GetSummary() (map[string]interface{}, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetSummaryCtx(context.Background())
+}
+
+// GetSummaryCtx ดึงสรุปบัญชี (รองรับ context)
+func (r *AccountService) GetSummaryCtx(ctx context.Context) (map[string]interface{}, error) {
+	ctx, span := r.client.startSpan(ctx, "AccountService.GetSummary")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var summary map[string]interface{}
-	err := r.client.get("/AccountSummary", queryParams, &summary)
+	err := r.client.get(ctx, "/AccountSummary", queryParams, &summary)
 	if err != nil {
 		return nil, err
 	}
@@ -66,18 +93,27 @@ func (r *AccountService) GetSummary() (map[string]interface{}, error) {
 
 // GetEquityHistory ดึงประวัติ Equity
 func (r *AccountService) GetEquityHistory(from, to string) ([]map[string]interface{}, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetEquityHistoryCtx(context.Background(), from, to)
+}
+
+// GetEquityHistoryCtx ดึงประวัติ Equity (รองรับ context)
+func (r *AccountService) GetEquityHistoryCtx(ctx context.Context, from, to string) ([]map[string]interface{}, error) {
+	ctx, span := r.client.startSpan(ctx, "AccountService.GetEquityHistory", Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":   r.client.token,
+		"id":   token,
 		"from": from,
 		"to":   to,
 	}
 
 	var history []map[string]interface{}
-	err := r.client.get("/EquityHistory", queryParams, &history)
+	err := r.client.get(ctx, "/EquityHistory", queryParams, &history)
 	if err != nil {
 		return nil, err
 	}
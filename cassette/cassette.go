@@ -0,0 +1,80 @@
+// Package cassette บันทึก (record) และเล่นซ้ำ (replay) traffic ของ mt5client
+// ทั้ง REST และ WebSocket ลงไฟล์ cassette โดยซ่อน token และรหัสผ่านก่อนบันทึก
+//
+// บันทึก:
+//
+//	rec := cassette.NewRecorder()
+//	client := mt5client.NewClient(url, mt5client.WithMiddleware(rec.Middleware()))
+//	ws := client.NewWebSocketClient()
+//	ws.AddObserver(rec)
+//	...
+//	rec.Save("testdata/order_send.json")
+//
+// เล่นซ้ำ:
+//
+//	c, _ := cassette.Load("testdata/order_send.json")
+//	srv := cassette.NewReplayServer(c)
+//	defer srv.Close()
+//	client := mt5client.NewClient(srv.URL)
+package cassette
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Redacted ค่าที่ใช้แทนข้อมูลลับใน cassette (ตรงกับที่ mt5client.RedactParams ใช้)
+const Redacted = "REDACTED"
+
+// Cassette traffic ที่บันทึกไว้
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+	Frames       []Frame       `json:"frames"`
+}
+
+// Interaction REST request/response หนึ่งคู่
+type Interaction struct {
+	Method     string            `json:"method"`
+	Endpoint   string            `json:"endpoint"`
+	Params     map[string]string `json:"params,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+	StatusCode int               `json:"statusCode,omitempty"`
+	Response   string            `json:"response,omitempty"`
+	Error      string            `json:"error,omitempty"` // error ระดับ network (ไม่มี response)
+}
+
+// Frame WebSocket frame หนึ่งข้อความ
+type Frame struct {
+	Path string `json:"path"`
+	Data string `json:"data"`
+}
+
+// Load อ่าน cassette จากไฟล์
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+
+	return &c, nil
+}
+
+// Save เขียน cassette ลงไฟล์
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package cassette
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ditthkr/mt5client"
+	"github.com/ditthkr/mt5client/mt5test"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := mt5test.NewServer()
+	defer srv.Close()
+	srv.AddAccount(mt5client.Account{Login: 1001, Balance: 5000}, "super-secret")
+	srv.AddSymbol(mt5test.ForexSymbol("EURUSD", 5))
+	srv.SetQuote("EURUSD", 1.1, 1.2)
+
+	// บันทึก
+	rec := NewRecorder()
+	client := mt5client.NewClient(srv.URL, mt5client.WithMiddleware(rec.Middleware()), mt5client.WithLogger(nil))
+	token, err := client.Connection.Connect(mt5client.ConnectParams{User: 1001, Password: "super-secret"})
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	recorded, err := client.Trading.Buy("EURUSD", 0.5, 0, 0)
+	if err != nil {
+		t.Fatalf("Buy failed: %v", err)
+	}
+
+	quotes := make(chan *mt5client.Quote, 1)
+	ws := client.NewWebSocketClient()
+	ws.AddObserver(rec)
+	ws.SetHandlers(&mt5client.EventHandlers{OnQuote: func(q *mt5client.Quote) { quotes <- q }})
+	ws.Connect()
+	if err := ws.SubscribeQuote(); err != nil {
+		t.Fatalf("SubscribeQuote failed: %v", err)
+	}
+	srv.WaitForStream("/OnQuote", 1, time.Second)
+	srv.SetQuote("EURUSD", 1.3, 1.4)
+	<-quotes
+	ws.Disconnect()
+
+	file := filepath.Join(t.TempDir(), "cassette.json")
+	if err := rec.Save(file); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	if strings.Contains(string(data), "super-secret") || strings.Contains(string(data), token) {
+		t.Fatalf("cassette leaks credentials:\n%s", data)
+	}
+
+	// เล่นซ้ำ
+	c, err := Load(file)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	replay := NewReplayServer(c)
+	defer replay.Close()
+
+	client = mt5client.NewClient(replay.URL, mt5client.WithLogger(nil))
+	if _, err := client.Connection.Connect(mt5client.ConnectParams{User: 1001, Password: "super-secret"}); err != nil {
+		t.Fatalf("replayed Connect failed: %v", err)
+	}
+	replayed, err := client.Trading.Buy("EURUSD", 0.5, 0, 0)
+	if err != nil {
+		t.Fatalf("replayed Buy failed: %v", err)
+	}
+	if replayed.Ticket != recorded.Ticket || replayed.OpenPrice != recorded.OpenPrice {
+		t.Errorf("replayed order %+v differs from recorded %+v", replayed, recorded)
+	}
+	if unused := replay.Unused(); len(unused) != 0 {
+		t.Errorf("expected all interactions replayed, %d left", len(unused))
+	}
+
+	ws = client.NewWebSocketClient()
+	ws.SetHandlers(&mt5client.EventHandlers{OnQuote: func(q *mt5client.Quote) { quotes <- q }})
+	ws.Connect()
+	defer ws.Disconnect()
+	if err := ws.SubscribeQuote(); err != nil {
+		t.Fatalf("replayed SubscribeQuote failed: %v", err)
+	}
+
+	select {
+	case q := <-quotes:
+		if q.Bid != 1.3 {
+			t.Errorf("expected replayed bid 1.3, got %f", q.Bid)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for replayed quote")
+	}
+}
+
+func TestRecorderRedactsEscapedBodyPassword(t *testing.T) {
+	srv := mt5test.NewServer()
+	defer srv.Close()
+	for login, password := range map[int64]string{1001: `p<ss&"word`, 1002: "a<"} {
+		srv.AddAccount(mt5client.Account{Login: login}, password)
+	}
+
+	rec := NewRecorder()
+	client := mt5client.NewClient(srv.URL,
+		mt5client.WithCredentialsInBody(),
+		mt5client.WithMiddleware(rec.Middleware()),
+		mt5client.WithLogger(nil),
+	)
+	if _, err := client.Connection.Connect(mt5client.ConnectParams{User: 1001, Password: `p<ss&"word`}); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	// รหัสผ่านสั้นกว่า 4 ตัวก็ต้องถูกซ่อน
+	if _, err := client.Connection.Connect(mt5client.ConnectParams{User: 1002, Password: "a<"}); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "cassette.json")
+	if err := rec.Save(file); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, _ := os.ReadFile(file)
+	for _, leak := range []string{`p\u003css\u0026`, `a\u003c`, "ss&"} {
+		if strings.Contains(string(data), leak) {
+			t.Fatalf("cassette leaks password %q:\n%s", leak, data)
+		}
+	}
+
+	c := rec.Cassette()
+	for _, in := range c.Interactions {
+		if string(in.Body) != `{"password":"REDACTED"}` {
+			t.Errorf("expected redacted body, got %s", in.Body)
+		}
+	}
+}
+
+func TestReplayWithTokenHeader(t *testing.T) {
+	srv := mt5test.NewServer()
+	defer srv.Close()
+	srv.SetTokenHeader("X-Session")
+	srv.AddAccount(mt5client.Account{Login: 1001, Balance: 5000}, "super-secret")
+
+	rec := NewRecorder()
+	client := mt5client.NewClient(srv.URL,
+		mt5client.WithTokenHeader("X-Session"),
+		mt5client.WithMiddleware(rec.Middleware()),
+		mt5client.WithLogger(nil),
+	)
+	if _, err := client.Connection.Connect(mt5client.ConnectParams{User: 1001, Password: "super-secret"}); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if _, err := client.Account.GetInfo(); err != nil {
+		t.Fatalf("GetInfo failed: %v", err)
+	}
+
+	replay := NewReplayServer(rec.Cassette())
+	defer replay.Close()
+
+	client = mt5client.NewClient(replay.URL, mt5client.WithTokenHeader("X-Session"), mt5client.WithLogger(nil))
+	if _, err := client.Connection.Connect(mt5client.ConnectParams{User: 1001, Password: "super-secret"}); err != nil {
+		t.Fatalf("replayed Connect failed: %v", err)
+	}
+	account, err := client.Account.GetInfo()
+	if err != nil {
+		t.Fatalf("replayed GetInfo failed: %v", err)
+	}
+	if account.Balance != 5000 {
+		t.Errorf("expected replayed balance 5000, got %f", account.Balance)
+	}
+}
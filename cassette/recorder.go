@@ -0,0 +1,180 @@
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/ditthkr/mt5client"
+)
+
+// connectEndpoints endpoint ที่ response เป็น session token
+var connectEndpoints = map[string]bool{
+	"/Connect":      true,
+	"/ConnectEx":    true,
+	"/ConnectProxy": true,
+}
+
+// Recorder บันทึก traffic ผ่าน middleware (REST) และ observer (WebSocket)
+type Recorder struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	frames       []Frame
+	secrets      map[string]struct{} // ค่าลับที่พบ ใช้ลบออกจากทุกข้อความตอน export
+}
+
+// NewRecorder สร้าง Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		secrets: make(map[string]struct{}),
+	}
+}
+
+// Middleware คืน middleware สำหรับบันทึกทุก REST exchange (รวมทุกครั้งที่ retry)
+func (r *Recorder) Middleware() mt5client.Middleware {
+	return func(next mt5client.RoundTrip) mt5client.RoundTrip {
+		return func(ctx context.Context, req *mt5client.APIRequest) (*mt5client.APIResponse, error) {
+			resp, err := next(ctx, req)
+			r.record(req, resp, err)
+			return resp, err
+		}
+	}
+}
+
+// record เก็บ interaction หนึ่งรายการ
+func (r *Recorder) record(req *mt5client.APIRequest, resp *mt5client.APIResponse, err error) {
+	interaction := Interaction{
+		Method:   req.Method,
+		Endpoint: req.Endpoint,
+	}
+
+	if len(req.Params) > 0 {
+		interaction.Params = make(map[string]string, len(req.Params))
+		for k, v := range req.Params {
+			interaction.Params[k] = v
+		}
+	}
+
+	if req.Body != nil {
+		payload := req.Body
+		// ซ่อนรหัสผ่านก่อน marshal เพราะ json escape อักขระพิเศษ ทำให้แทนค่าด้วย string ภายหลังไม่เจอ
+		if fields, ok := req.Body.(map[string]string); ok {
+			payload = mt5client.RedactParams(fields)
+		}
+		if body, marshalErr := json.Marshal(payload); marshalErr == nil {
+			interaction.Body = body
+		}
+	}
+
+	if resp != nil {
+		interaction.StatusCode = resp.StatusCode
+		interaction.Response = string(resp.Body)
+	} else if err != nil {
+		interaction.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, v := range req.Params {
+		if mt5client.IsSecretParam(key) && v != "" {
+			r.secrets[v] = struct{}{}
+		}
+	}
+	if body, ok := req.Body.(map[string]string); ok {
+		for key, v := range body {
+			if mt5client.IsSecretParam(key) && v != "" {
+				r.secrets[v] = struct{}{}
+			}
+		}
+	}
+	if connectEndpoints[req.Endpoint] && resp != nil && resp.StatusCode == 200 {
+		if token := strings.Trim(strings.TrimSpace(interaction.Response), `"`); token != "" {
+			r.secrets[token] = struct{}{}
+		}
+	}
+
+	r.interactions = append(r.interactions, interaction)
+}
+
+// OnFrame implements mt5client.WebSocketFrameObserver
+func (r *Recorder) OnFrame(path string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.frames = append(r.frames, Frame{Path: path, Data: string(data)})
+}
+
+// OnMessage implements mt5client.WebSocketObserver
+func (r *Recorder) OnMessage(path string) {}
+
+// OnParseError implements mt5client.WebSocketObserver
+func (r *Recorder) OnParseError(path string, err error) {}
+
+// OnReconnect implements mt5client.WebSocketObserver
+func (r *Recorder) OnReconnect(path string) {}
+
+// OnConnectionState implements mt5client.WebSocketObserver
+func (r *Recorder) OnConnectionState(path string, up bool) {}
+
+// Cassette คืน traffic ที่บันทึกไว้ โดยซ่อนข้อมูลลับแล้ว
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	redact := r.redactor()
+	c := &Cassette{
+		Interactions: make([]Interaction, 0, len(r.interactions)),
+		Frames:       make([]Frame, 0, len(r.frames)),
+	}
+
+	for _, in := range r.interactions {
+		out := in
+		if in.Params != nil {
+			out.Params = mt5client.RedactParams(in.Params)
+		}
+		if connectEndpoints[in.Endpoint] && in.StatusCode == 200 {
+			out.Response = Redacted
+		} else {
+			out.Response = redact(in.Response)
+		}
+		out.Error = redact(in.Error)
+		if in.Body != nil {
+			out.Body = json.RawMessage(redact(string(in.Body)))
+		}
+		c.Interactions = append(c.Interactions, out)
+	}
+
+	for _, f := range r.frames {
+		c.Frames = append(c.Frames, Frame{Path: f.Path, Data: redact(f.Data)})
+	}
+
+	return c
+}
+
+// Save บันทึก cassette ลงไฟล์
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+// redactor คืนฟังก์ชันลบค่าลับทั้งหมดออกจากข้อความ (ต้องถือ r.mu อยู่)
+func (r *Recorder) redactor() func(string) string {
+	pairs := make([]string, 0, len(r.secrets)*2)
+	for secret := range r.secrets {
+		// ค่าสั้นเกินไปอาจไปตรงกับข้อมูลปกติ
+		if len(secret) < 4 {
+			continue
+		}
+		pairs = append(pairs, secret, Redacted)
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	return func(s string) string {
+		if s == "" || len(pairs) == 0 {
+			return s
+		}
+		return replacer.Replace(s)
+	}
+}
+
+var _ mt5client.WebSocketFrameObserver = (*Recorder)(nil)
@@ -0,0 +1,159 @@
+package cassette
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/ditthkr/mt5client"
+	"github.com/gorilla/websocket"
+)
+
+// ReplayServer เซิร์ฟเวอร์ที่ตอบกลับจาก cassette ตามลำดับที่บันทึกไว้
+type ReplayServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// NewReplayServer สร้างและเริ่ม ReplayServer
+func NewReplayServer(c *Cassette) *ReplayServer {
+	s := &ReplayServer{
+		cassette: c,
+		used:     make([]bool, len(c.Interactions)),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Unused คืน interaction ที่ยังไม่ถูกเล่นซ้ำ
+func (s *ReplayServer) Unused() []Interaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var unused []Interaction
+	for i, used := range s.used {
+		if !used {
+			unused = append(unused, s.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+// serveHTTP ตอบ REST จาก interactions และ WebSocket จาก frames
+func (s *ReplayServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveFrames(w, r)
+		return
+	}
+
+	interaction, ok := s.next(r)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"code":    "NO_RECORDING",
+			"message": "no recorded interaction for " + r.Method + " " + r.URL.Path,
+		})
+		return
+	}
+
+	// error ระดับ network: ตัด connection โดยไม่ตอบ
+	if interaction.Error != "" && interaction.StatusCode == 0 {
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	w.WriteHeader(interaction.StatusCode)
+	w.Write([]byte(interaction.Response))
+}
+
+// next หา interaction แรกที่ยังไม่ถูกใช้และตรงกับ request
+func (s *ReplayServer) next(r *http.Request) (Interaction, bool) {
+	params := make(map[string]string)
+	for k, values := range r.URL.Query() {
+		if len(values) > 0 {
+			params[k] = values[0]
+		}
+	}
+	params = mt5client.RedactParams(params)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, in := range s.cassette.Interactions {
+		if s.used[i] || in.Method != r.Method || in.Endpoint != r.URL.Path {
+			continue
+		}
+		if !sameParams(in.Params, params) {
+			continue
+		}
+		s.used[i] = true
+		return in, true
+	}
+
+	return Interaction{}, false
+}
+
+// sameParams เปรียบเทียบ params สองชุด โดยไม่สน "id"
+// (token ถูกซ่อนไว้อยู่แล้ว และไม่มีใน query เมื่อ client ใช้ WithTokenHeader)
+func sameParams(a, b map[string]string) bool {
+	a, b = withoutToken(a), withoutToken(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutToken คัดลอก params โดยตัด "id" ออก
+func withoutToken(params map[string]string) map[string]string {
+	out := make(map[string]string, len(params))
+	for k, v := range params {
+		if k != "id" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// serveFrames ส่ง frames ของ path ตามลำดับแล้วเปิด connection ค้างไว้จน client ปิด
+func (s *ReplayServer) serveFrames(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	for _, frame := range s.cassette.Frames {
+		if frame.Path != r.URL.Path {
+			continue
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(frame.Data)); err != nil {
+			return
+		}
+	}
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
@@ -2,19 +2,52 @@ package mt5client
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
 // Client โครงสร้างหลักสำหรับเชื่อมต่อกับ MT5 REST API
+//
+// Client หนึ่งตัวใช้ร่วมกันจากหลาย goroutine ได้: token และ Session ถูกป้องกันด้วย mutex
+// แต่ละ service call อ่าน token ครั้งเดียวตอนเริ่ม ถ้า token ถูกเปลี่ยนระหว่างนั้น (SetToken, Connect, re-login)
+// call ที่กำลังทำงานอยู่จะใช้ token เดิมจนจบ ค่าที่ตั้งผ่าน Option ห้ามแก้หลัง NewClient
+// ถ้าต้องการหลายบัญชีให้ใช้ Client แยกกัน (ดู AccountPool)
 type Client struct {
-	baseURL    string
-	token      string
-	httpClient *http.Client
+	baseURL          string
+	basePath         string
+	mu               sync.RWMutex // ป้องกัน token และ session
+	token            string
+	httpClient       *http.Client
+	timeout          *time.Duration // จาก WithTimeout (nil = ไม่ได้กำหนด)
+	headers          http.Header
+	endpointTimeouts map[string]time.Duration
+	groupTimeouts    map[EndpointGroup]time.Duration
+	requestTimeout   time.Duration // timeout รวมที่ใช้เป็น context deadline เมื่อมี endpoint/group timeout
+	tlsConfig        *tls.Config
+	retryPolicy      RetryPolicy
+	limiter          requestLimiter
+	groupLimiters    map[EndpointGroup]*requestLimiter
+	middlewares      []Middleware
+	roundTrip        RoundTrip
+	logger           *slog.Logger
+	tracer           Tracer
+	tokenHeader      string
+	credentialsBody  bool
+	session          *Session // ตั้งโดย NewSession ใช้ re-login อัตโนมัติ
+	orderValidator   *OrderValidator
+	symbolParams     *OrderValidator // cache SymbolParams สำหรับตรวจ policy ของ pending order
+	idempotentOrders bool
+	idempotency      IdempotencyConfig
 
 	// Services
 	Connection   *ConnectionService
@@ -30,19 +63,38 @@ type Client struct {
 	Service      *ServiceFunctions
 }
 
-// NewClient สร้าง Client ใหม่
-func NewClient(baseURL string) *Client {
+// NewClient สร้าง Client ใหม่ (ปรับแต่งเพิ่มเติมได้ผ่าน Option เช่น WithHTTPClient, WithTimeout)
+func NewClient(baseURL string, opts ...Option) *Client {
 	if baseURL == "" {
 		baseURL = "http://localhost:5000"
 	}
 
 	c := &Client{
-		baseURL: baseURL,
+		baseURL: strings.TrimRight(baseURL, "/"),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		headers:          make(http.Header),
+		endpointTimeouts: make(map[string]time.Duration),
+		groupTimeouts:    make(map[EndpointGroup]time.Duration),
+		retryPolicy:      DefaultRetryPolicy(),
+		idempotency:      DefaultIdempotencyConfig(),
+		groupLimiters:    make(map[EndpointGroup]*requestLimiter),
+		logger:           slog.Default(),
+		tracer:           noopTracer{},
 	}
 
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.applyHTTPOptions()
+	c.symbolParams = c.orderValidator
+	if c.symbolParams == nil {
+		c.symbolParams = c.NewOrderValidator()
+	}
+
+	c.roundTrip = chainMiddlewares(c.send, c.middlewares)
+
 	c.Connection = &ConnectionService{client: c}
 	c.Account = &AccountService{client: c}
 	c.Trading = &TradingService{client: c}
@@ -60,77 +112,271 @@ func NewClient(baseURL string) *Client {
 
 // SetToken ตั้งค่า token
 func (r *Client) SetToken(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.token = token
 }
 
 // GetToken รับค่า token
 func (r *Client) GetToken() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.token
 }
 
-// doRequest ส่ง HTTP request
-func (r *Client) doRequest(method, endpoint string, params map[string]string, body interface{}, result interface{}) error {
-	fullURL := r.baseURL + endpoint
+// compareAndSetToken ตั้ง token เป็น next เฉพาะเมื่อ token ปัจจุบันยังเป็น old
+func (r *Client) compareAndSetToken(old, next string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token != old {
+		return false
+	}
+	r.token = next
+	return true
+}
+
+// getSession คืน Session ที่ผูกอยู่ (nil ถ้าไม่มี)
+func (r *Client) getSession() *Session {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.session
+}
+
+// doRequest ส่ง HTTP request โดยผูกกับ ctx (cancel/deadline จะถูกส่งต่อไปยัง HTTP call)
+// endpoint ที่ idempotent จะถูก retry ตาม retryPolicy
+// ถ้าผูกกับ Session และ server ตอบว่า session หมดอายุ จะ re-login ทุกครั้ง
+// แต่ส่งใหม่หนึ่งครั้งเฉพาะ endpoint ที่ retry ได้ (เช่น /OrderSend จะคืน error ให้ผู้เรียกตัดสินใจเอง)
+func (r *Client) doRequest(ctx context.Context, method, endpoint string, params map[string]string, body interface{}, result interface{}) error {
+	err := r.doAttempts(ctx, method, endpoint, params, body, result)
+	session := r.getSession()
+	if session == nil || params["id"] == "" || !errors.Is(err, ErrSessionExpired) {
+		return err
+	}
+
+	token, reconnectErr := session.reconnect(ctx, params["id"])
+	if reconnectErr != nil {
+		return fmt.Errorf("%w (re-login failed: %v)", err, reconnectErr)
+	}
+	if !isRetryableEndpoint(ctx, endpoint) {
+		return err
+	}
+
+	retried := make(map[string]string, len(params))
+	for k, v := range params {
+		retried[k] = v
+	}
+	retried["id"] = token
+
+	return r.doAttempts(ctx, method, endpoint, retried, body, result)
+}
+
+// doAttempts ส่ง request พร้อม retry ตาม retryPolicy
+func (r *Client) doAttempts(ctx context.Context, method, endpoint string, params map[string]string, body interface{}, result interface{}) error {
+	maxAttempts := 1
+	if isRetryableEndpoint(ctx, endpoint) && r.retryPolicy.MaxAttempts > 1 {
+		maxAttempts = r.retryPolicy.MaxAttempts
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = r.tracedRoundTrip(ctx, &APIRequest{
+			Method:   method,
+			Endpoint: endpoint,
+			Params:   params,
+			Body:     body,
+			Result:   result,
+			Attempt:  attempt,
+		})
+		if attempt == maxAttempts || !r.retryPolicy.shouldRetry(ctx, err) {
+			return err
+		}
+
+		delay := r.retryPolicy.backoff(attempt)
+		r.logger.LogAttrs(ctx, slog.LevelWarn, "retrying request",
+			slog.String("method", method),
+			slog.String("endpoint", endpoint),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.Any("error", err),
+		)
+
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// tracedRoundTrip ส่ง request หนึ่งครั้งผ่าน middleware chain ภายใต้ span ของ HTTP call
+func (r *Client) tracedRoundTrip(ctx context.Context, req *APIRequest) error {
+	ctx, span := r.startSpan(ctx, "HTTP "+req.Method+" "+req.Endpoint,
+		Attr("http.method", req.Method),
+		Attr("endpoint", req.Endpoint),
+		Attr("attempt", req.Attempt),
+	)
+	defer span.End()
+
+	resp, err := r.roundTrip(ctx, req)
+	if resp != nil {
+		span.SetAttributes(Attr("http.status_code", resp.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+// endpointTimeout timeout ของ endpoint ตามลำดับ endpoint, group, timeout รวม (0 = ใช้ http.Client.Timeout)
+func (r *Client) endpointTimeout(endpoint string) time.Duration {
+	if timeout := r.endpointTimeouts[endpoint]; timeout > 0 {
+		return timeout
+	}
+	if timeout := r.groupTimeouts[endpointGroup(endpoint)]; timeout > 0 {
+		return timeout
+	}
+	return r.requestTimeout
+}
 
-	if len(params) > 0 {
+// send ส่ง HTTP request หนึ่งครั้งและ decode ผลลัพธ์ (เป็น RoundTrip ชั้นในสุดของ middleware chain)
+func (r *Client) send(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+	release, err := r.acquire(ctx, req.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if timeout := r.endpointTimeout(req.Endpoint); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	fullURL := r.baseURL + r.basePath + req.Endpoint
+
+	// ถ้าตั้ง tokenHeader ไว้ token จะถูกส่งผ่าน header แทน query "id"
+	var token string
+	if len(req.Params) > 0 {
 		values := url.Values{}
-		for k, v := range params {
+		for k, v := range req.Params {
+			if k == "id" && r.tokenHeader != "" {
+				token = v
+				continue
+			}
 			values.Add(k, v)
 		}
-		fullURL += "?" + values.Encode()
+		if len(values) > 0 {
+			fullURL += "?" + values.Encode()
+		}
 	}
 
 	var bodyReader io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
+	if req.Body != nil {
+		jsonData, err := json.Marshal(req.Body)
 		if err != nil {
-			return fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
 		bodyReader = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequest(method, fullURL, bodyReader)
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, fullURL, bodyReader)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	for key, values := range r.headers {
+		for _, value := range values {
+			httpReq.Header.Add(key, value)
+		}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		httpReq.Header.Set(r.tokenHeader, token)
+	}
 
-	resp, err := r.httpClient.Do(req)
+	start := time.Now()
+	resp, err := r.httpClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		err = redactError(err)
+		r.logger.LogAttrs(ctx, slog.LevelDebug, "request failed",
+			slog.String("method", req.Method),
+			slog.String("endpoint", req.Endpoint),
+			slog.Duration("duration", time.Since(start)),
+			slog.Any("error", err),
+		)
+		return nil, &sendError{err: err}
 	}
 	defer resp.Body.Close()
 
+	r.logger.LogAttrs(ctx, slog.LevelDebug, "request completed",
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.Endpoint),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("duration", time.Since(start)),
+	)
+
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return DecodeResponse(req, resp.StatusCode, respBody)
+}
+
+// DecodeResponse แปลง response body ลงใน req.Result แบบเดียวกับที่ Client ทำ
+// (ใช้ใน middleware ที่ตอบกลับเอง เช่น cache หรือ fault injection)
+func DecodeResponse(req *APIRequest, statusCode int, body []byte) (*APIResponse, error) {
+	resp := &APIResponse{
+		StatusCode: statusCode,
+		Body:       body,
+		Result:     req.Result,
 	}
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody))
+	if statusCode != 200 {
+		return resp, newAPIError(req.Method, req.Endpoint, statusCode, body)
 	}
 
-	if result != nil {
-		if strResult, ok := result.(*string); ok {
-			*strResult = string(respBody)
-			return nil
+	if req.Result != nil {
+		if strResult, ok := req.Result.(*string); ok {
+			*strResult = string(body)
+			return resp, nil
 		}
 
-		if err := json.Unmarshal(respBody, result); err != nil {
-			return fmt.Errorf("failed to parse response: %w, body: %s", err, string(respBody))
+		if err := json.Unmarshal(body, req.Result); err != nil {
+			return resp, fmt.Errorf("failed to parse response: %w, body: %s", err, string(body))
 		}
 	}
 
-	return nil
+	return resp, nil
 }
 
 // get ส่ง GET request
-func (r *Client) get(endpoint string, params map[string]string, result interface{}) error {
-	return r.doRequest("GET", endpoint, params, nil, result)
+func (r *Client) get(ctx context.Context, endpoint string, params map[string]string, result interface{}) error {
+	return r.doRequest(ctx, "GET", endpoint, params, nil, result)
 }
 
 // post ส่ง POST request
-func (r *Client) post(endpoint string, params map[string]string, body interface{}, result interface{}) error {
-	return r.doRequest("POST", endpoint, params, body, result)
+func (r *Client) post(ctx context.Context, endpoint string, params map[string]string, body interface{}, result interface{}) error {
+	return r.doRequest(ctx, "POST", endpoint, params, body, result)
+}
+
+// sendCredentials ส่ง request ที่มีรหัสผ่าน
+// ปกติส่งทุกอย่างผ่าน query (GET) ถ้าเปิด WithCredentialsInBody รหัสผ่านจะย้ายไปอยู่ใน JSON body (POST)
+func (r *Client) sendCredentials(ctx context.Context, endpoint string, params map[string]string, result interface{}) error {
+	if !r.credentialsBody {
+		return r.get(ctx, endpoint, params, result)
+	}
+
+	query := make(map[string]string, len(params))
+	body := make(map[string]string)
+	for k, v := range params {
+		if k != "id" && IsSecretParam(k) {
+			body[k] = v
+		} else {
+			query[k] = v
+		}
+	}
+
+	return r.post(ctx, endpoint, query, body, result)
 }
@@ -0,0 +1,126 @@
+package mt5client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestDoRequestContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL)
+	client.SetToken("token")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Trading.SendCtx(ctx, OrderRequest{Symbol: "EURUSD", Type: "Buy", Volume: 0.01})
+	if err == nil {
+		t.Fatal("expected error from cancelled context")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestClientConcurrentTokenAccess ใช้ Client เดียวจากหลาย goroutine รวมถึง WebSocket ที่ reconnect อยู่ (ตรวจด้วย go test -race)
+func TestClientConcurrentTokenAccess(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ConnectEx":
+			w.Write([]byte("token-new"))
+		case "/Account":
+			w.Write([]byte(`{"login":1001}`))
+		case "/OnQuote":
+			// ส่ง quote หนึ่งครั้งแล้วปิด เพื่อให้ client เข้า reconnectPath
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"Quote","data":{"symbol":"EURUSD","bid":1.1}}`))
+			time.Sleep(5 * time.Millisecond)
+			conn.Close()
+		default:
+			w.Write([]byte("OK"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	session := NewSession(client)
+	client.SetToken("token-initial")
+
+	ws := session.NewWebSocketClient()
+	ws.SetLogger(nil)
+	ws.SetAutoReconnect(true, time.Millisecond)
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("websocket Connect failed: %v", err)
+	}
+	defer ws.Disconnect()
+	if err := ws.SubscribeQuote(); err != nil {
+		t.Fatalf("SubscribeQuote failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				switch (i + j) % 6 {
+				case 0:
+					client.SetToken("token-set")
+				case 1:
+					client.Account.GetInfo()
+				case 2:
+					client.Connection.IsConnected()
+				case 3:
+					session.ConnectEx(1001, "secret", "Demo-Server")
+				case 4:
+					client.Connection.Disconnect()
+				case 5:
+					ws.SetHandlers(&EventHandlers{OnQuote: func(*Quote) {}, OnError: func(error) {}})
+				}
+				_ = client.GetToken()
+				time.Sleep(time.Millisecond)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestDisconnectKeepsNewerToken(t *testing.T) {
+	var client *Client
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// จำลองการเชื่อมต่อใหม่จาก goroutine อื่นระหว่างที่ Disconnect ยังไม่เสร็จ
+		client.SetToken("token-new")
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	client = NewClient(server.URL, WithLogger(nil))
+	client.SetToken("token-old")
+
+	if err := client.Connection.Disconnect(); err != nil {
+		t.Fatalf("Disconnect failed: %v", err)
+	}
+	if got := client.GetToken(); got != "token-new" {
+		t.Errorf("expected newer token to survive Disconnect, got %q", got)
+	}
+}
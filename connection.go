@@ -1,6 +1,9 @@
 package mt5client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // ConnectionService จัดการการเชื่อมต่อ
 type ConnectionService struct {
@@ -9,6 +12,14 @@ type ConnectionService struct {
 
 // Connect เชื่อมต่อ MT5
 func (r *ConnectionService) Connect(params ConnectParams) (string, error) {
+	return r.ConnectCtx(context.Background(), params)
+}
+
+// ConnectCtx เชื่อมต่อ MT5 (รองรับ context)
+func (r *ConnectionService) ConnectCtx(ctx context.Context, params ConnectParams) (string, error) {
+	ctx, span := r.client.startSpan(ctx, "ConnectionService.Connect", Attr("user", params.User), Attr("host", params.Host))
+	defer span.End()
+
 	queryParams := map[string]string{
 		"user":     fmt.Sprintf("%d", params.User),
 		"password": params.Password,
@@ -17,7 +28,7 @@ func (r *ConnectionService) Connect(params ConnectParams) (string, error) {
 	}
 
 	var token string
-	err := r.client.get("/Connect", queryParams, &token)
+	err := r.client.sendCredentials(ctx, "/Connect", queryParams, &token)
 	if err != nil {
 		return "", err
 	}
@@ -28,6 +39,14 @@ func (r *ConnectionService) Connect(params ConnectParams) (string, error) {
 
 // ConnectEx เชื่อมต่อแบบ Extended (ใช้ชื่อ server แทน host/port)
 func (r *ConnectionService) ConnectEx(user int64, password, server string) (string, error) {
+	return r.ConnectExCtx(context.Background(), user, password, server)
+}
+
+// ConnectExCtx เชื่อมต่อแบบ Extended (ใช้ชื่อ server แทน host/port) (รองรับ context)
+func (r *ConnectionService) ConnectExCtx(ctx context.Context, user int64, password, server string) (string, error) {
+	ctx, span := r.client.startSpan(ctx, "ConnectionService.ConnectEx", Attr("user", user), Attr("server", server))
+	defer span.End()
+
 	queryParams := map[string]string{
 		"user":     fmt.Sprintf("%d", user),
 		"password": password,
@@ -35,7 +54,7 @@ func (r *ConnectionService) ConnectEx(user int64, password, server string) (stri
 	}
 
 	var token string
-	err := r.client.get("/ConnectEx", queryParams, &token)
+	err := r.client.sendCredentials(ctx, "/ConnectEx", queryParams, &token)
 	if err != nil {
 		return "", err
 	}
@@ -46,6 +65,14 @@ func (r *ConnectionService) ConnectEx(user int64, password, server string) (stri
 
 // ConnectProxy เชื่อมต่อผ่าน Proxy
 func (r *ConnectionService) ConnectProxy(params ConnectParams, proxyType, proxyHost string, proxyPort int) (string, error) {
+	return r.ConnectProxyCtx(context.Background(), params, proxyType, proxyHost, proxyPort)
+}
+
+// ConnectProxyCtx เชื่อมต่อผ่าน Proxy (รองรับ context)
+func (r *ConnectionService) ConnectProxyCtx(ctx context.Context, params ConnectParams, proxyType, proxyHost string, proxyPort int) (string, error) {
+	ctx, span := r.client.startSpan(ctx, "ConnectionService.ConnectProxy", Attr("user", params.User), Attr("host", params.Host))
+	defer span.End()
+
 	queryParams := map[string]string{
 		"user":      fmt.Sprintf("%d", params.User),
 		"password":  params.Password,
@@ -57,7 +84,7 @@ func (r *ConnectionService) ConnectProxy(params ConnectParams, proxyType, proxyH
 	}
 
 	var token string
-	err := r.client.get("/ConnectProxy", queryParams, &token)
+	err := r.client.sendCredentials(ctx, "/ConnectProxy", queryParams, &token)
 	if err != nil {
 		return "", err
 	}
@@ -66,38 +93,120 @@ func (r *ConnectionService) ConnectProxy(params ConnectParams, proxyType, proxyH
 	return token, nil
 }
 
+// ConnectViaProxy เชื่อมต่อผ่าน proxy ที่ตรวจสอบค่าแล้ว
+func (r *ConnectionService) ConnectViaProxy(params ConnectParams, proxy ProxyConfig) (string, error) {
+	return r.ConnectViaProxyCtx(context.Background(), params, proxy)
+}
+
+// ConnectViaProxyCtx เชื่อมต่อผ่าน proxy ที่ตรวจสอบค่าแล้ว (รองรับ context)
+func (r *ConnectionService) ConnectViaProxyCtx(ctx context.Context, params ConnectParams, proxy ProxyConfig) (string, error) {
+	ctx, span := r.client.startSpan(ctx, "ConnectionService.ConnectProxy", Attr("user", params.User), Attr("host", params.Host), Attr("proxy", proxy.String()))
+	defer span.End()
+
+	if err := proxy.Validate(); err != nil {
+		return "", err
+	}
+
+	queryParams := proxy.queryParams()
+	queryParams["user"] = fmt.Sprintf("%d", params.User)
+	queryParams["password"] = params.Password
+	queryParams["host"] = params.Host
+	queryParams["port"] = fmt.Sprintf("%d", params.Port)
+
+	var token string
+	err := r.client.sendCredentials(ctx, "/ConnectProxy", queryParams, &token)
+	if err != nil {
+		return "", err
+	}
+
+	r.client.SetToken(token)
+	return token, nil
+}
+
+// ConnectWith เชื่อมต่อด้วยข้อมูลจาก CredentialProvider
+func (r *ConnectionService) ConnectWith(provider CredentialProvider) (string, error) {
+	return r.ConnectWithCtx(context.Background(), provider)
+}
+
+// ConnectWithCtx เชื่อมต่อด้วยข้อมูลจาก CredentialProvider (รองรับ context)
+// รหัสผ่านถูกอ่านตอนเรียกเท่านั้นและไม่ถูกเก็บไว้ใน Client
+func (r *ConnectionService) ConnectWithCtx(ctx context.Context, provider CredentialProvider) (string, error) {
+	creds, err := provider.Credentials(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	params := ConnectParams{
+		User:     creds.User,
+		Password: creds.Password,
+		Host:     creds.Host,
+		Port:     creds.Port,
+	}
+
+	switch {
+	case creds.Proxy != nil:
+		if creds.Host == "" {
+			return "", fmt.Errorf("%w: connecting through a proxy requires host", ErrInvalidArgument)
+		}
+		return r.ConnectViaProxyCtx(ctx, params, *creds.Proxy)
+	case creds.Server != "":
+		return r.ConnectExCtx(ctx, creds.User, creds.Password, creds.Server)
+	default:
+		return r.ConnectCtx(ctx, params)
+	}
+}
+
 // Disconnect ตัดการเชื่อมต่อ
 func (r *ConnectionService) Disconnect() error {
-	if r.client.token == "" {
+	return r.DisconnectCtx(context.Background())
+}
+
+// DisconnectCtx ตัดการเชื่อมต่อ (รองรับ context)
+func (r *ConnectionService) DisconnectCtx(ctx context.Context) error {
+	ctx, span := r.client.startSpan(ctx, "ConnectionService.Disconnect")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
 		return nil
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var result string
-	err := r.client.get("/Disconnect", queryParams, &result)
+	err := r.client.get(ctx, "/Disconnect", queryParams, &result)
 	if err != nil {
 		return err
 	}
 
-	r.client.SetToken("")
+	// ถ้าระหว่างนี้มีการเชื่อมต่อใหม่ (token เปลี่ยน) ไม่ล้าง token ใหม่นั้น
+	r.client.compareAndSetToken(token, "")
 	return nil
 }
 
 // IsConnected ตรวจสอบสถานะการเชื่อมต่อ
 func (r *ConnectionService) IsConnected() (bool, error) {
-	if r.client.token == "" {
+	return r.IsConnectedCtx(context.Background())
+}
+
+// IsConnectedCtx ตรวจสอบสถานะการเชื่อมต่อ (รองรับ context)
+func (r *ConnectionService) IsConnectedCtx(ctx context.Context) (bool, error) {
+	ctx, span := r.client.startSpan(ctx, "ConnectionService.IsConnected")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
 		return false, nil
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var result string
-	err := r.client.get("/CheckConnect", queryParams, &result)
+	err := r.client.get(ctx, "/CheckConnect", queryParams, &result)
 	if err != nil {
 		return false, err
 	}
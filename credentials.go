@@ -0,0 +1,159 @@
+package mt5client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Credentials ข้อมูลเข้าสู่ระบบ MT5
+// ถ้ากำหนด Proxy จะเชื่อมต่อด้วย ConnectViaProxy (ต้องมี Host/Port)
+// ถ้ากำหนด Server จะเชื่อมต่อด้วย ConnectEx ไม่เช่นนั้นใช้ Host/Port กับ Connect
+type Credentials struct {
+	User     int64        `json:"user"`
+	Password string       `json:"password"`
+	Server   string       `json:"server,omitempty"`
+	Host     string       `json:"host,omitempty"`
+	Port     int          `json:"port,omitempty"`
+	Proxy    *ProxyConfig `json:"proxy,omitempty"`
+}
+
+// String ไม่แสดงรหัสผ่าน (กันหลุดเวลา log ด้วย %v)
+func (c Credentials) String() string {
+	s := fmt.Sprintf("Credentials{User: %d, Server: %q, Host: %q, Port: %d", c.User, c.Server, c.Host, c.Port)
+	if c.Proxy != nil {
+		s += ", Proxy: " + c.Proxy.String()
+	}
+	return s + "}"
+}
+
+// CredentialProvider แหล่งข้อมูลเข้าสู่ระบบ
+// ถูกเรียกทุกครั้งที่ต้องเชื่อมต่อ เพื่อไม่ต้องเก็บรหัสผ่านไว้ใน struct ของแอปตลอดเวลา
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialFunc ใช้ฟังก์ชันธรรมดาเป็น CredentialProvider
+type CredentialFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials implements CredentialProvider
+func (f CredentialFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// EnvCredentials อ่านข้อมูลเข้าสู่ระบบจาก environment variables
+// ตัวแปรที่ไม่ได้ตั้งชื่อไว้จะถูกข้าม (User และ Password จำเป็น)
+type EnvCredentials struct {
+	UserVar     string // เช่น "MT5_USER"
+	PasswordVar string // เช่น "MT5_PASSWORD"
+	ServerVar   string
+	HostVar     string
+	PortVar     string
+}
+
+// Credentials implements CredentialProvider
+func (e EnvCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	var creds Credentials
+
+	user, err := strconv.ParseInt(os.Getenv(e.UserVar), 10, 64)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("%w: invalid user in $%s", ErrInvalidArgument, e.UserVar)
+	}
+	creds.User = user
+
+	creds.Password = os.Getenv(e.PasswordVar)
+	if creds.Password == "" {
+		return Credentials{}, fmt.Errorf("%w: $%s is not set", ErrInvalidArgument, e.PasswordVar)
+	}
+
+	if e.ServerVar != "" {
+		creds.Server = os.Getenv(e.ServerVar)
+	}
+	if e.HostVar != "" {
+		creds.Host = os.Getenv(e.HostVar)
+	}
+	if e.PortVar != "" {
+		if port := os.Getenv(e.PortVar); port != "" {
+			if creds.Port, err = strconv.Atoi(port); err != nil {
+				return Credentials{}, fmt.Errorf("%w: invalid port in $%s", ErrInvalidArgument, e.PortVar)
+			}
+		}
+	}
+
+	return creds, nil
+}
+
+// FileCredentials อ่านข้อมูลเข้าสู่ระบบจากไฟล์ JSON ทุกครั้งที่ถูกเรียก
+// รูปแบบ: {"user": 1001, "password": "...", "server": "Demo-Server"}
+type FileCredentials string
+
+// Credentials implements CredentialProvider
+func (path FileCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	data, err := os.ReadFile(string(path))
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read credentials: %w", err)
+	}
+
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return Credentials{}, fmt.Errorf("failed to parse credentials file %s: %w", path, err)
+	}
+	if creds.User == 0 || creds.Password == "" {
+		return Credentials{}, fmt.Errorf("%w: credentials file %s requires user and password", ErrInvalidArgument, path)
+	}
+
+	return creds, nil
+}
+
+// SecretStore ที่เก็บความลับแบบ key/value (เช่น Vault, AWS Secrets Manager)
+type SecretStore interface {
+	GetSecret(ctx context.Context, key string) (string, error)
+}
+
+// MapSecretStore SecretStore ในหน่วยความจำ ใช้แทน vault จริงในการทดสอบหรือ development
+type MapSecretStore map[string]string
+
+// GetSecret implements SecretStore
+func (m MapSecretStore) GetSecret(ctx context.Context, key string) (string, error) {
+	value, ok := m[key]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found", key)
+	}
+	return value, nil
+}
+
+// SecretStoreCredentials อ่านข้อมูลเข้าสู่ระบบจาก SecretStore ตาม key ที่กำหนด
+type SecretStoreCredentials struct {
+	Store       SecretStore
+	UserKey     string
+	PasswordKey string
+	ServerKey   string // ไม่บังคับ
+}
+
+// Credentials implements CredentialProvider
+func (s SecretStoreCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	userValue, err := s.Store.GetSecret(ctx, s.UserKey)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	user, err := strconv.ParseInt(userValue, 10, 64)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("%w: invalid user in secret %q", ErrInvalidArgument, s.UserKey)
+	}
+
+	password, err := s.Store.GetSecret(ctx, s.PasswordKey)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to get password: %w", err)
+	}
+
+	creds := Credentials{User: user, Password: password}
+	if s.ServerKey != "" {
+		if creds.Server, err = s.Store.GetSecret(ctx, s.ServerKey); err != nil {
+			return Credentials{}, fmt.Errorf("failed to get server: %w", err)
+		}
+	}
+
+	return creds, nil
+}
@@ -0,0 +1,102 @@
+package mt5client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithCredentialsInBody(t *testing.T) {
+	var method, queryPassword string
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		queryPassword = r.URL.Query().Get("password")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte("token-1"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithCredentialsInBody())
+	if _, err := client.Connection.ConnectEx(1001, "hunter2", "Demo-Server"); err != nil {
+		t.Fatalf("ConnectEx failed: %v", err)
+	}
+
+	if method != http.MethodPost {
+		t.Errorf("expected POST, got %s", method)
+	}
+	if queryPassword != "" {
+		t.Errorf("password leaked into query: %q", queryPassword)
+	}
+	if body["password"] != "hunter2" {
+		t.Errorf("expected password in body, got %v", body)
+	}
+}
+
+func TestConnectWithProviders(t *testing.T) {
+	var gotUser, gotServer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = r.URL.Query().Get("user")
+		gotServer = r.URL.Query().Get("server")
+		w.Write([]byte("token-1"))
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "creds.json")
+	os.WriteFile(file, []byte(`{"user": 1003, "password": "p", "server": "File-Server"}`), 0o600)
+
+	t.Setenv("TEST_MT5_USER", "1002")
+	t.Setenv("TEST_MT5_PASSWORD", "p")
+	t.Setenv("TEST_MT5_SERVER", "Env-Server")
+
+	providers := map[string]CredentialProvider{
+		"1001|Func-Server": CredentialFunc(func(ctx context.Context) (Credentials, error) {
+			return Credentials{User: 1001, Password: "p", Server: "Func-Server"}, nil
+		}),
+		"1002|Env-Server":  EnvCredentials{UserVar: "TEST_MT5_USER", PasswordVar: "TEST_MT5_PASSWORD", ServerVar: "TEST_MT5_SERVER"},
+		"1003|File-Server": FileCredentials(file),
+		"1004|Vault-Server": SecretStoreCredentials{
+			Store:       MapSecretStore{"mt5/user": "1004", "mt5/password": "p", "mt5/server": "Vault-Server"},
+			UserKey:     "mt5/user",
+			PasswordKey: "mt5/password",
+			ServerKey:   "mt5/server",
+		},
+	}
+
+	client := NewClient(server.URL)
+	for want, provider := range providers {
+		if _, err := client.Connection.ConnectWith(provider); err != nil {
+			t.Fatalf("%s: ConnectWith failed: %v", want, err)
+		}
+		if got := gotUser + "|" + gotServer; got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	}
+}
+
+func TestCredentialProviderErrors(t *testing.T) {
+	client := NewClient("http://localhost:1")
+
+	_, err := client.Connection.ConnectWith(EnvCredentials{UserVar: "TEST_MT5_MISSING_USER", PasswordVar: "TEST_MT5_MISSING_PASSWORD"})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument, got %v", err)
+	}
+
+	_, err = client.Connection.ConnectWith(SecretStoreCredentials{Store: MapSecretStore{}, UserKey: "user", PasswordKey: "password"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected missing secret error, got %v", err)
+	}
+}
+
+func TestCredentialsStringHidesPassword(t *testing.T) {
+	creds := Credentials{User: 1001, Password: "hunter2"}
+	if s := creds.String(); strings.Contains(s, "hunter2") {
+		t.Errorf("password leaked: %s", s)
+	}
+}
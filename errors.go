@@ -0,0 +1,109 @@
+package mt5client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors ใช้ร่วมกับ errors.Is
+var (
+	// ErrNotConnected ยังไม่ได้เชื่อมต่อ (ไม่มี token)
+	ErrNotConnected = errors.New("not connected")
+	// ErrSessionExpired server ไม่รู้จัก token แล้ว (session หมดอายุหรือถูกตัด)
+	ErrSessionExpired = errors.New("session expired")
+	// ErrSymbolNotFound ไม่พบสัญลักษณ์ในโบรกเกอร์
+	ErrSymbolNotFound = errors.New("symbol not found")
+	// ErrInvalidArgument พารามิเตอร์ที่ส่งเข้ามาไม่ถูกต้อง (ตรวจฝั่ง client)
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrRequestRejected server ปฏิเสธคำขอ (เช่น broker reject คำสั่ง)
+	ErrRequestRejected = errors.New("request rejected")
+	// ErrServer server ตอบกลับด้วย 5xx
+	ErrServer = errors.New("server error")
+	// ErrOrderStateUnknown ส่งคำสั่งแล้วไม่ได้คำตอบที่ชัดเจน และตรวจสอบไม่ได้ว่าคำสั่งถูกวางแล้วหรือไม่
+	ErrOrderStateUnknown = errors.New("order state unknown")
+)
+
+// APIError error ที่ได้จาก MT5 REST API (status code != 200)
+type APIError struct {
+	StatusCode int    // HTTP status code
+	Method     string // HTTP method
+	Endpoint   string // endpoint เช่น /OrderSend
+	Body       string // response body ดิบ
+	Code       string // MT5 retcode/code (ถ้า parse ได้)
+	Message    string // ข้อความจาก server (ถ้า parse ได้)
+
+	kind error // sentinel ที่จัดประเภทแล้ว
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Body
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("%s %s: status %d: %s (%s)", e.Method, e.Endpoint, e.StatusCode, msg, e.Code)
+	}
+	return fmt.Sprintf("%s %s: status %d: %s", e.Method, e.Endpoint, e.StatusCode, msg)
+}
+
+// Unwrap คืน sentinel error เพื่อให้ใช้ errors.Is ได้
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// newAPIError สร้าง APIError จาก response และจัดประเภท error
+func newAPIError(method, endpoint string, statusCode int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Endpoint:   endpoint,
+		Body:       string(body),
+	}
+
+	// server มักตอบเป็น JSON {"code": ..., "message": ...} แต่บางครั้งเป็น text ธรรมดา
+	var payload struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		e.Code = strings.Trim(string(payload.Code), `"`)
+		e.Message = payload.Message
+		if e.Message == "" {
+			e.Message = payload.Error
+		}
+	} else {
+		e.Message = strings.Trim(strings.TrimSpace(string(body)), `"`)
+	}
+
+	e.kind = classifyAPIError(statusCode, e.Code, e.Message)
+	return e
+}
+
+// classifyAPIError จัดประเภท error จาก status code และข้อความ
+func classifyAPIError(statusCode int, code, message string) error {
+	text := strings.ToLower(code + " " + message)
+
+	// จับเฉพาะ token ที่ไม่ถูกต้องเท่านั้น ข้อความอื่นที่มีคำว่า "session" หรือ "not connected"
+	// (เช่น trade session ปิด, trade server not connected) เป็นการปฏิเสธคำสั่ง ไม่ใช่ token หมดอายุ
+	switch {
+	case statusCode == http.StatusUnauthorized,
+		strings.Contains(text, "invalid_token"),
+		strings.Contains(text, "invalid token"),
+		strings.Contains(text, "invalid id"):
+		return ErrSessionExpired
+	case strings.Contains(text, "invalid_symbol"),
+		strings.Contains(text, "symbol not found"),
+		strings.Contains(text, "unknown symbol"),
+		strings.Contains(text, "symbol not exist"):
+		return ErrSymbolNotFound
+	case statusCode >= 500:
+		return ErrServer
+	default:
+		return ErrRequestRejected
+	}
+}
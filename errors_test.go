@@ -0,0 +1,104 @@
+package mt5client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIErrorClassification(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+		code   string
+	}{
+		{
+			name:   "invalid token JSON",
+			status: http.StatusBadRequest,
+			body:   `{"code":"INVALID_TOKEN","message":"Invalid token"}`,
+			want:   ErrSessionExpired,
+			code:   "INVALID_TOKEN",
+		},
+		{
+			name:   "unauthorized",
+			status: http.StatusUnauthorized,
+			body:   "unauthorized",
+			want:   ErrSessionExpired,
+		},
+		{
+			name:   "trade session closed is a rejection",
+			status: http.StatusBadRequest,
+			body:   `{"code":"MARKET_CLOSED","message":"Trade session is closed"}`,
+			want:   ErrRequestRejected,
+			code:   "MARKET_CLOSED",
+		},
+		{
+			name:   "trade server not connected is a rejection",
+			status: http.StatusBadRequest,
+			body:   "Trade server not connected",
+			want:   ErrRequestRejected,
+		},
+		{
+			name:   "symbol not found text",
+			status: http.StatusBadRequest,
+			body:   "Symbol not found: FOOBAR",
+			want:   ErrSymbolNotFound,
+		},
+		{
+			name:   "broker rejection",
+			status: http.StatusBadRequest,
+			body:   `{"code":10019,"message":"No money"}`,
+			want:   ErrRequestRejected,
+			code:   "10019",
+		},
+		{
+			name:   "server error",
+			status: http.StatusBadGateway,
+			body:   "bad gateway",
+			want:   ErrServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, WithRetryPolicy(NoRetryPolicy()))
+			client.SetToken("token")
+
+			_, err := client.Account.GetInfo()
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError, got %T", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, apiErr.StatusCode)
+			}
+			if apiErr.Endpoint != "/Account" {
+				t.Errorf("expected endpoint /Account, got %s", apiErr.Endpoint)
+			}
+			if apiErr.Code != tt.code {
+				t.Errorf("expected code %q, got %q", tt.code, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestErrNotConnected(t *testing.T) {
+	client := NewClient("http://127.0.0.1:0")
+
+	if _, err := client.Order.GetOpened(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("expected ErrNotConnected, got %v", err)
+	}
+}
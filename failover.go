@@ -0,0 +1,178 @@
+package mt5client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServerCandidate access server ที่ใช้ลองเชื่อมต่อด้วย ConnectEx
+type ServerCandidate struct {
+	Server string // ชื่อที่ส่งให้ ConnectEx
+	Host   string // host ที่ใช้ ping (ว่าง = ใช้ Server)
+}
+
+// pingHost host ที่ใช้ ping
+func (c ServerCandidate) pingHost() string {
+	if c.Host != "" {
+		return c.Host
+	}
+	return c.Server
+}
+
+// ServerRank ผลการจัดอันดับ candidate หนึ่งตัว
+type ServerRank struct {
+	ServerCandidate
+	Reachable bool          // ผลจาก PingHostMany
+	Latency   time.Duration // เวลาที่ใช้ PingHost (เฉพาะตัวที่ reachable)
+}
+
+// FailoverResult ผลของ ConnectFailover
+type FailoverResult struct {
+	Token    string
+	Chosen   ServerCandidate // server ที่เชื่อมต่อสำเร็จ
+	Ranked   []ServerRank    // ลำดับที่ใช้ลองเชื่อมต่อ
+	Attempts map[string]error
+}
+
+// RankServers จัดอันดับ candidates: ตัวที่ ping ได้เรียงตาม latency ก่อน ตามด้วยตัวที่ ping ไม่ได้ตามลำดับเดิม
+func (r *ServiceFunctions) RankServers(candidates []ServerCandidate) ([]ServerRank, error) {
+	return r.RankServersCtx(context.Background(), candidates)
+}
+
+// RankServersCtx จัดอันดับ candidates (รองรับ context)
+func (r *ServiceFunctions) RankServersCtx(ctx context.Context, candidates []ServerCandidate) ([]ServerRank, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: no server candidates", ErrInvalidArgument)
+	}
+
+	hosts := make([]string, len(candidates))
+	for i, c := range candidates {
+		hosts[i] = c.pingHost()
+	}
+
+	reachable, err := r.PingHostManyCtx(ctx, hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := make([]ServerRank, len(candidates))
+	var wg sync.WaitGroup
+	for i, c := range candidates {
+		ranks[i] = ServerRank{ServerCandidate: c, Reachable: reachable[c.pingHost()]}
+		if !ranks[i].Reachable {
+			continue
+		}
+
+		wg.Add(1)
+		go func(rank *ServerRank) {
+			defer wg.Done()
+			start := time.Now()
+			ok, err := r.PingHostCtx(ctx, rank.pingHost())
+			rank.Latency = time.Since(start)
+			if err != nil || !ok {
+				rank.Reachable = false
+			}
+		}(&ranks[i])
+	}
+	wg.Wait()
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].Reachable != ranks[j].Reachable {
+			return ranks[i].Reachable
+		}
+		return ranks[i].Reachable && ranks[i].Latency < ranks[j].Latency
+	})
+
+	return ranks, nil
+}
+
+// ConnectFailover เชื่อมต่อด้วย ConnectEx ไปยัง server ที่ดีที่สุดจาก candidates และลองตัวถัดไปถ้าล้มเหลว
+// (เฉพาะ server ติดต่อไม่ได้หรือ 5xx ถ้าบัญชีถูกปฏิเสธ เช่นรหัสผ่านผิด จะหยุดทันที)
+func (r *ConnectionService) ConnectFailover(user int64, password string, candidates []ServerCandidate) (*FailoverResult, error) {
+	return r.ConnectFailoverCtx(context.Background(), user, password, candidates)
+}
+
+// ConnectFailoverCtx เชื่อมต่อแบบ failover (รองรับ context)
+func (r *ConnectionService) ConnectFailoverCtx(ctx context.Context, user int64, password string, candidates []ServerCandidate) (*FailoverResult, error) {
+	ctx, span := r.client.startSpan(ctx, "ConnectionService.ConnectFailover", Attr("user", user), Attr("candidates", len(candidates)))
+	defer span.End()
+
+	ranks, err := r.client.Service.RankServersCtx(ctx, candidates)
+	if err != nil {
+		// ping ไม่ได้ก็ยังลองเชื่อมต่อตามลำดับที่ให้มา
+		if errors.Is(err, ErrInvalidArgument) {
+			return nil, err
+		}
+		ranks = make([]ServerRank, len(candidates))
+		for i, c := range candidates {
+			ranks[i] = ServerRank{ServerCandidate: c}
+		}
+	}
+
+	result := &FailoverResult{Ranked: ranks, Attempts: make(map[string]error)}
+	var errs []error
+	for _, rank := range ranks {
+		token, err := r.ConnectExCtx(ctx, user, password, rank.Server)
+		if err == nil {
+			result.Token = token
+			result.Chosen = rank.ServerCandidate
+			span.SetAttributes(Attr("server", rank.Server))
+			return result, nil
+		}
+
+		result.Attempts[rank.Server] = err
+		errs = append(errs, fmt.Errorf("%s: %w", rank.Server, err))
+		if ctx.Err() != nil {
+			break
+		}
+		if !shouldFailover(err) {
+			// เช่นรหัสผ่านผิด ลอง server อื่นก็ไม่ช่วยและเสี่ยงโดนล็อกบัญชี
+			err = fmt.Errorf("connect to %s rejected: %w", rank.Server, err)
+			span.RecordError(err)
+			return result, err
+		}
+	}
+
+	err = fmt.Errorf("all servers failed: %w", errors.Join(errs...))
+	span.RecordError(err)
+	return result, err
+}
+
+// serverUnavailableMarkers ข้อความ/code ของการปฏิเสธที่หมายถึง server ใช้ไม่ได้ (ไม่ใช่ข้อมูลบัญชีผิด)
+var serverUnavailableMarkers = []string{
+	"connect_failed",
+	"cannot reach",
+	"server not found",
+	"invalid_server",
+	"invalid server",
+	"unknown server",
+	"no connection",
+	"timed out",
+	"timeout",
+}
+
+// shouldFailover ตรวจสอบว่า error จาก ConnectEx ควรลอง server ถัดไปหรือไม่
+// ลองต่อเฉพาะ error ระดับ transport, 5xx และ server ไม่พบ/ติดต่อไม่ได้ ส่วนการปฏิเสธบัญชีให้หยุด
+func shouldFailover(err error) bool {
+	var sendErr *sendError
+	if errors.As(err, &sendErr) || errors.Is(err, ErrServer) {
+		return true
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	text := strings.ToLower(apiErr.Code + " " + apiErr.Message)
+	for _, marker := range serverUnavailableMarkers {
+		if strings.Contains(text, marker) {
+			return true
+		}
+	}
+	return false
+}
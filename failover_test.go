@@ -0,0 +1,100 @@
+package mt5client_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ditthkr/mt5client"
+	"github.com/ditthkr/mt5client/mt5test"
+)
+
+// newFailoverGateway gateway ที่ far ตอบช้า, down เข้าถึงไม่ได้ และ server ใน down ล่ม
+func newFailoverGateway(t *testing.T, down ...string) (*mt5test.Server, *mt5client.Client) {
+	t.Helper()
+
+	srv := mt5test.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddAccount(mt5client.Account{Login: testLogin, Currency: "USD"}, "secret")
+	srv.SetHost("far.example.com", 30*time.Millisecond, true)
+	srv.SetHost("down.example.com", 0, false)
+	for _, server := range down {
+		srv.SetServerDown(server, true)
+	}
+
+	return srv, mt5client.NewClient(srv.URL, mt5client.WithLogger(nil))
+}
+
+var failoverCandidates = []mt5client.ServerCandidate{
+	{Server: "Broker-Down", Host: "down.example.com"},
+	{Server: "Broker-Far", Host: "far.example.com"},
+	{Server: "Broker-Near", Host: "near.example.com"},
+}
+
+func TestRankServers(t *testing.T) {
+	_, client := newFailoverGateway(t)
+
+	ranks, err := client.Service.RankServers(failoverCandidates)
+	if err != nil {
+		t.Fatalf("RankServers failed: %v", err)
+	}
+
+	want := []string{"Broker-Near", "Broker-Far", "Broker-Down"}
+	for i, rank := range ranks {
+		if rank.Server != want[i] {
+			t.Errorf("rank %d: expected %s, got %s", i, want[i], rank.Server)
+		}
+	}
+	if ranks[2].Reachable {
+		t.Error("expected down server to be unreachable")
+	}
+}
+
+func TestConnectFailoverFallsBack(t *testing.T) {
+	srv, client := newFailoverGateway(t, "Broker-Near")
+
+	result, err := client.Connection.ConnectFailover(testLogin, "secret", failoverCandidates)
+	if err != nil {
+		t.Fatalf("ConnectFailover failed: %v", err)
+	}
+	if result.Chosen.Server != "Broker-Far" {
+		t.Errorf("expected Broker-Far, got %s", result.Chosen.Server)
+	}
+	if got := srv.LastQuery("/ConnectEx").Get("server"); got != "Broker-Far" {
+		t.Errorf("expected last ConnectEx to Broker-Far, got %s", got)
+	}
+	if client.GetToken() != result.Token || result.Token == "" {
+		t.Errorf("unexpected token %q (result %q)", client.GetToken(), result.Token)
+	}
+	if _, ok := result.Attempts["Broker-Near"]; !ok {
+		t.Error("expected failed attempt on Broker-Near to be reported")
+	}
+}
+
+func TestConnectFailoverAllFail(t *testing.T) {
+	_, client := newFailoverGateway(t, "Broker-Near", "Broker-Far", "Broker-Down")
+
+	result, err := client.Connection.ConnectFailover(testLogin, "secret", failoverCandidates)
+	if !errors.Is(err, mt5client.ErrRequestRejected) {
+		t.Fatalf("expected ErrRequestRejected, got %v", err)
+	}
+	if len(result.Attempts) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(result.Attempts))
+	}
+}
+
+func TestConnectFailoverStopsOnAuthRejection(t *testing.T) {
+	srv, client := newFailoverGateway(t)
+
+	result, err := client.Connection.ConnectFailover(testLogin, "wrong", failoverCandidates)
+	if !errors.Is(err, mt5client.ErrRequestRejected) {
+		t.Fatalf("expected ErrRequestRejected, got %v", err)
+	}
+	if len(result.Attempts) != 1 {
+		t.Errorf("expected to stop after 1 attempt, got %d", len(result.Attempts))
+	}
+	if n := srv.RequestCount("/ConnectEx"); n != 1 {
+		t.Errorf("expected 1 ConnectEx request, got %d", n)
+	}
+}
@@ -0,0 +1,71 @@
+package mt5client
+
+import "strings"
+
+// OrderFilter เงื่อนไขกรอง Order, HistoryPosition และ Deal ตาม magic number และ comment tag
+// ใช้แยกคำสั่งของแต่ละ bot/กลยุทธ์ที่เทรดในบัญชีเดียวกัน
+type OrderFilter struct {
+	MagicNumbers []int64 // ตรงกับ magic number ใดก็ได้ในรายการ (ว่าง = ไม่กรอง)
+	CommentTag   string  // comment ต้องขึ้นต้นด้วย tag นี้ (ว่าง = ไม่กรอง)
+}
+
+// ByMagic กรองตาม magic number (ตรงกับค่าใดก็ได้)
+func ByMagic(magic ...int64) OrderFilter {
+	return OrderFilter{MagicNumbers: magic}
+}
+
+// ByCommentTag กรองตาม comment ที่ขึ้นต้นด้วย tag
+// ใช้ prefix เพราะ server อาจต่อท้าย comment เอง เช่น "[sl]" หรือ "[tp]" ตอนปิด
+func ByCommentTag(tag string) OrderFilter {
+	return OrderFilter{CommentTag: tag}
+}
+
+// Match ตรวจสอบ magic number และ comment กับเงื่อนไข
+func (f OrderFilter) Match(magic int64, comment string) bool {
+	if len(f.MagicNumbers) > 0 {
+		found := false
+		for _, m := range f.MagicNumbers {
+			if m == magic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return strings.HasPrefix(comment, f.CommentTag)
+}
+
+// FilterOrders คืนเฉพาะคำสั่งที่ตรงกับ filter
+func FilterOrders(orders []Order, filter OrderFilter) []Order {
+	result := make([]Order, 0, len(orders))
+	for _, order := range orders {
+		if filter.Match(order.MagicNumber, order.Comment) {
+			result = append(result, order)
+		}
+	}
+	return result
+}
+
+// FilterPositions คืนเฉพาะตำแหน่งที่ตรงกับ filter
+func FilterPositions(positions []HistoryPosition, filter OrderFilter) []HistoryPosition {
+	result := make([]HistoryPosition, 0, len(positions))
+	for _, position := range positions {
+		if filter.Match(position.MagicNumber, position.Comment) {
+			result = append(result, position)
+		}
+	}
+	return result
+}
+
+// FilterDeals คืนเฉพาะดีลที่ตรงกับ filter
+func FilterDeals(deals []Deal, filter OrderFilter) []Deal {
+	result := make([]Deal, 0, len(deals))
+	for _, deal := range deals {
+		if filter.Match(deal.MagicNumber, deal.Comment) {
+			result = append(result, deal)
+		}
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package mt5client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrderFilterMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  OrderFilter
+		magic   int64
+		comment string
+		want    bool
+	}{
+		{"empty filter", OrderFilter{}, 7, "anything", true},
+		{"magic match", ByMagic(1, 2), 2, "", true},
+		{"magic mismatch", ByMagic(1, 2), 3, "", false},
+		{"manual orders", ByMagic(0), 0, "", true},
+		{"tag prefix", ByCommentTag("grid"), 0, "grid#12 [sl]", true},
+		{"tag mismatch", ByCommentTag("grid"), 0, "scalper", false},
+		{"both", OrderFilter{MagicNumbers: []int64{5}, CommentTag: "grid"}, 5, "scalper", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Match(tt.magic, tt.comment); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestFilterOrders(t *testing.T) {
+	orders := []Order{
+		{Ticket: 1, MagicNumber: 100, Comment: "grid"},
+		{Ticket: 2, MagicNumber: 200, Comment: "scalper"},
+		{Ticket: 3, MagicNumber: 100, Comment: "hedge"},
+	}
+
+	filtered := FilterOrders(orders, ByMagic(100))
+	if len(filtered) != 2 || filtered[0].Ticket != 1 || filtered[1].Ticket != 3 {
+		t.Errorf("unexpected orders %+v", filtered)
+	}
+
+	positions := FilterPositions([]HistoryPosition{{PositionId: 1, Comment: "grid"}, {PositionId: 2}}, ByCommentTag("grid"))
+	if len(positions) != 1 || positions[0].PositionId != 1 {
+		t.Errorf("unexpected positions %+v", positions)
+	}
+}
+
+func TestOrderMagicNumberFromExpertID(t *testing.T) {
+	var order Order
+	if err := json.Unmarshal([]byte(`{"ticket":1,"expertId":42}`), &order); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if order.MagicNumber != 42 {
+		t.Errorf("expected magic number 42, got %d", order.MagicNumber)
+	}
+}
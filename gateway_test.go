@@ -0,0 +1,30 @@
+package mt5client_test
+
+import (
+	"testing"
+
+	"github.com/ditthkr/mt5client"
+	"github.com/ditthkr/mt5client/mt5test"
+)
+
+// testLogin บัญชีที่ newGateway สร้างไว้
+const testLogin = 1001
+
+// newGateway สร้าง fake gateway ที่มี EURUSD และ client ที่ login แล้ว
+func newGateway(t *testing.T, opts ...mt5client.Option) (*mt5test.Server, *mt5client.Client) {
+	t.Helper()
+
+	srv := mt5test.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddAccount(mt5client.Account{Login: testLogin, Balance: 10000, Currency: "USD"}, "secret")
+	srv.AddSymbol(mt5test.ForexSymbol("EURUSD", 5))
+	srv.SetQuote("EURUSD", 1.10000, 1.10020)
+
+	client := mt5client.NewClient(srv.URL, append([]mt5client.Option{mt5client.WithLogger(nil)}, opts...)...)
+	if _, err := client.Connection.Connect(mt5client.ConnectParams{User: testLogin, Password: "secret"}); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	return srv, client
+}
@@ -0,0 +1,327 @@
+package mt5client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ConnectionState สถานะการเชื่อมต่อที่ HealthMonitor ติดตาม
+type ConnectionState int
+
+const (
+	StateConnecting   ConnectionState = iota // ยังไม่ได้ตรวจสอบครั้งแรก
+	StateConnected                           // /Ping และ /CheckConnect ปกติ
+	StateDegraded                            // ตรวจผ่านแต่ latency สูง
+	StateDisconnected                        // ตรวจไม่ผ่านติดกันถึง FailureThreshold
+	StateReconnecting                        // กำลัง re-login ผ่าน Session
+	StateFailing                             // ตรวจไม่ผ่านแต่ยังไม่ถึง FailureThreshold (หยุดเทรดไว้ก่อน)
+)
+
+// String ชื่อสถานะ
+func (s ConnectionState) String() string {
+	switch s {
+	case StateConnecting:
+		return "Connecting"
+	case StateConnected:
+		return "Connected"
+	case StateDegraded:
+		return "Degraded"
+	case StateDisconnected:
+		return "Disconnected"
+	case StateReconnecting:
+		return "Reconnecting"
+	case StateFailing:
+		return "Failing"
+	default:
+		return fmt.Sprintf("ConnectionState(%d)", int(s))
+	}
+}
+
+// CanTrade สถานะที่ควรส่งคำสั่งซื้อขายได้ (Connected หรือ Degraded)
+// การตรวจที่ไม่ผ่านแม้ครั้งเดียวจะไม่ให้สถานะที่เทรดได้
+func (s ConnectionState) CanTrade() bool {
+	return s == StateConnected || s == StateDegraded
+}
+
+// StateChange เหตุการณ์เปลี่ยนสถานะ
+type StateChange struct {
+	From    ConnectionState
+	To      ConnectionState
+	Latency time.Duration // latency ของ /Ping ครั้งล่าสุด
+	Err     error         // สาเหตุ (ถ้าตรวจไม่ผ่าน)
+	At      time.Time
+}
+
+// HealthStatus สรุปสถานะล่าสุด
+type HealthStatus struct {
+	State               ConnectionState
+	Latency             time.Duration // latency ของ /Ping ครั้งล่าสุด
+	AverageLatency      time.Duration // ค่าเฉลี่ยถ่วงน้ำหนัก (EWMA)
+	LastCheck           time.Time
+	LastError           error
+	ConsecutiveFailures int
+}
+
+// HealthConfig ค่าตั้งของ HealthMonitor
+type HealthConfig struct {
+	Interval         time.Duration // ระยะห่างระหว่างการตรวจ (default: 10 วินาที)
+	Timeout          time.Duration // timeout ของการตรวจแต่ละครั้ง (default: 5 วินาที)
+	DegradedLatency  time.Duration // latency ที่ถือว่า Degraded (default: 1 วินาที)
+	FailureThreshold int           // ตรวจไม่ผ่านติดกันกี่ครั้งจึงเป็น Disconnected (default: 3)
+	Session          *Session      // ถ้ากำหนด จะ re-login เมื่อเป็น Disconnected
+}
+
+// DefaultHealthConfig ค่าตั้งเริ่มต้น
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		Interval:         10 * time.Second,
+		Timeout:          5 * time.Second,
+		DegradedLatency:  time.Second,
+		FailureThreshold: 3,
+	}
+}
+
+// HealthMonitor ตรวจสุขภาพการเชื่อมต่อเป็นระยะด้วย /Ping และ /CheckConnect
+//
+//	monitor := mt5client.NewHealthMonitor(client, mt5client.DefaultHealthConfig())
+//	monitor.OnStateChange(func(c mt5client.StateChange) {
+//		if !c.To.CanTrade() { pauseTrading() }
+//	})
+//	monitor.Start(ctx)
+//	defer monitor.Stop()
+type HealthMonitor struct {
+	client *Client
+	config HealthConfig
+
+	mu          sync.RWMutex
+	status      HealthStatus
+	callbacks   []func(StateChange)
+	subscribers []chan StateChange
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// NewHealthMonitor สร้าง HealthMonitor (ค่าที่เป็นศูนย์ใน config จะใช้ค่า default)
+func NewHealthMonitor(client *Client, config HealthConfig) *HealthMonitor {
+	defaults := DefaultHealthConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.DegradedLatency <= 0 {
+		config.DegradedLatency = defaults.DegradedLatency
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaults.FailureThreshold
+	}
+
+	return &HealthMonitor{
+		client: client,
+		config: config,
+		status: HealthStatus{State: StateConnecting},
+	}
+}
+
+// OnStateChange เพิ่ม callback ที่จะถูกเรียกทุกครั้งที่สถานะเปลี่ยน (เรียกจาก goroutine ของ monitor)
+func (m *HealthMonitor) OnStateChange(fn func(StateChange)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callbacks = append(m.callbacks, fn)
+}
+
+// Subscribe คืน channel ที่ได้รับเหตุการณ์เปลี่ยนสถานะ
+// ถ้าผู้รับอ่านไม่ทัน เหตุการณ์จะถูกทิ้ง (ใช้ State() เพื่อดูสถานะล่าสุด)
+// channel จะถูกปิดเมื่อ Stop
+func (m *HealthMonitor) Subscribe() <-chan StateChange {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch := make(chan StateChange, 16)
+	m.subscribers = append(m.subscribers, ch)
+	return ch
+}
+
+// State สถานะปัจจุบัน
+func (m *HealthMonitor) State() ConnectionState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status.State
+}
+
+// Status สรุปสถานะล่าสุด
+func (m *HealthMonitor) Status() HealthStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// Start เริ่มตรวจใน background (ตรวจครั้งแรกทันที) จนกว่า ctx ถูก cancel หรือเรียก Stop
+func (m *HealthMonitor) Start(ctx context.Context) {
+	m.mu.Lock()
+	if m.cancel != nil {
+		m.mu.Unlock()
+		return
+	}
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	done := m.done
+	m.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			m.Check(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop หยุดตรวจ รอ goroutine จบ และปิด channel ของ Subscribe
+func (m *HealthMonitor) Stop() {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel = nil
+	m.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+
+	m.mu.Lock()
+	for _, ch := range m.subscribers {
+		close(ch)
+	}
+	m.subscribers = nil
+	m.mu.Unlock()
+}
+
+// Check ตรวจหนึ่งครั้งและอัปเดตสถานะ คืน error ถ้าตรวจไม่ผ่าน
+func (m *HealthMonitor) Check(ctx context.Context) error {
+	return m.check(ctx, m.config.Session != nil)
+}
+
+// check ตรวจหนึ่งครั้ง ถ้า allowReconnect จะ re-login เมื่อเป็น Disconnected
+func (m *HealthMonitor) check(ctx context.Context, allowReconnect bool) error {
+	latency, err := m.probe(ctx)
+	if ctx.Err() != nil {
+		// ถูกหยุดระหว่างตรวจ ไม่นับเป็นความล้มเหลว
+		return err
+	}
+
+	m.mu.Lock()
+	m.status.LastCheck = time.Now()
+	m.status.LastError = err
+	if err != nil {
+		m.status.ConsecutiveFailures++
+	} else {
+		m.status.ConsecutiveFailures = 0
+		m.status.Latency = latency
+		if m.status.AverageLatency == 0 {
+			m.status.AverageLatency = latency
+		} else {
+			m.status.AverageLatency = (m.status.AverageLatency*4 + latency) / 5
+		}
+	}
+
+	next := StateConnected
+	switch {
+	case m.status.ConsecutiveFailures >= m.config.FailureThreshold:
+		next = StateDisconnected
+	case err != nil:
+		next = StateFailing
+	case latency > m.config.DegradedLatency:
+		next = StateDegraded
+	}
+	m.mu.Unlock()
+
+	m.transition(next, err)
+
+	if next == StateDisconnected && allowReconnect {
+		return m.reconnect(ctx)
+	}
+	return err
+}
+
+// probe เรียก /Ping (วัด latency) แล้ว /CheckConnect
+func (m *HealthMonitor) probe(ctx context.Context) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := m.client.Service.PingCtx(ctx); err != nil {
+		return 0, fmt.Errorf("ping failed: %w", err)
+	}
+	latency := time.Since(start)
+
+	connected, err := m.client.Connection.IsConnectedCtx(ctx)
+	if err != nil {
+		return latency, fmt.Errorf("check connect failed: %w", err)
+	}
+	if !connected {
+		return latency, fmt.Errorf("%w: terminal is not connected", ErrNotConnected)
+	}
+
+	return latency, nil
+}
+
+// reconnect re-login ผ่าน Session แล้วตรวจใหม่
+// ไม่ล้าง ConsecutiveFailures ก่อนตรวจ ถ้าตรวจไม่ผ่านจึงกลับเป็น Disconnected (และ re-login อีกครั้งในรอบถัดไป)
+func (m *HealthMonitor) reconnect(ctx context.Context) error {
+	m.transition(StateReconnecting, nil)
+
+	if _, err := m.config.Session.ReconnectCtx(ctx); err != nil {
+		m.transition(StateDisconnected, err)
+		return err
+	}
+
+	return m.check(ctx, false)
+}
+
+// transition เปลี่ยนสถานะและแจ้ง callbacks/subscribers ถ้าสถานะเปลี่ยนจริง
+func (m *HealthMonitor) transition(to ConnectionState, err error) {
+	m.mu.Lock()
+	from := m.status.State
+	if from == to {
+		m.mu.Unlock()
+		return
+	}
+	m.status.State = to
+	change := StateChange{From: from, To: to, Latency: m.status.Latency, Err: err, At: time.Now()}
+	callbacks := make([]func(StateChange), len(m.callbacks))
+	copy(callbacks, m.callbacks)
+	for _, ch := range m.subscribers {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+	m.mu.Unlock()
+
+	attrs := []slog.Attr{
+		slog.String("from", from.String()),
+		slog.String("to", to.String()),
+		slog.Duration("latency", change.Latency),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	m.client.logger.LogAttrs(context.Background(), slog.LevelInfo, "connection state changed", attrs...)
+
+	for _, fn := range callbacks {
+		fn(change)
+	}
+}
@@ -0,0 +1,161 @@
+package mt5client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// healthServer จำลอง gateway ที่ปรับ latency และสถานะ terminal ได้
+type healthServer struct {
+	mu        sync.Mutex
+	delay     time.Duration
+	connected bool
+}
+
+func (s *healthServer) set(delay time.Duration, connected bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay, s.connected = delay, connected
+}
+
+func (s *healthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	delay, connected := s.delay, s.connected
+	s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/Ping":
+		time.Sleep(delay)
+		w.Write([]byte("pong"))
+	case "/Connect":
+		w.Write([]byte("token"))
+	case "/CheckConnect":
+		if connected {
+			w.Write([]byte("OK"))
+		} else {
+			w.Write([]byte("FAIL"))
+		}
+	}
+}
+
+func TestHealthMonitorStateTransitions(t *testing.T) {
+	backend := &healthServer{connected: true}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	client.SetToken("token")
+
+	monitor := NewHealthMonitor(client, HealthConfig{
+		DegradedLatency:  20 * time.Millisecond,
+		FailureThreshold: 2,
+	})
+	ctx := context.Background()
+
+	var changes []StateChange
+	monitor.OnStateChange(func(c StateChange) { changes = append(changes, c) })
+
+	steps := []struct {
+		delay     time.Duration
+		connected bool
+		want      ConnectionState
+	}{
+		{0, true, StateConnected},
+		{40 * time.Millisecond, true, StateDegraded},
+		{0, true, StateConnected},
+		{0, false, StateFailing},
+		{0, false, StateDisconnected},
+		{0, true, StateConnected},
+	}
+	for i, step := range steps {
+		backend.set(step.delay, step.connected)
+		err := monitor.Check(ctx)
+		if got := monitor.State(); got != step.want {
+			t.Fatalf("step %d: expected %s, got %s", i, step.want, got)
+		}
+		if err != nil && monitor.State().CanTrade() {
+			t.Fatalf("step %d: failed check left tradable state %s", i, monitor.State())
+		}
+	}
+
+	if len(changes) != len(steps) {
+		t.Errorf("expected %d state changes, got %d", len(steps), len(changes))
+	}
+	if changes[0].From != StateConnecting {
+		t.Errorf("expected first change from Connecting, got %s", changes[0].From)
+	}
+	if status := monitor.Status(); status.AverageLatency <= 0 || status.ConsecutiveFailures != 0 {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestHealthMonitorSubscribe(t *testing.T) {
+	backend := &healthServer{connected: true}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	client.SetToken("token")
+
+	monitor := NewHealthMonitor(client, HealthConfig{Interval: 10 * time.Millisecond, FailureThreshold: 1})
+	events := monitor.Subscribe()
+	monitor.Start(context.Background())
+
+	expect := func(want ConnectionState) {
+		t.Helper()
+		select {
+		case c := <-events:
+			if c.To != want {
+				t.Fatalf("expected %s, got %s", want, c.To)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+
+	expect(StateConnected)
+	backend.set(0, false)
+	expect(StateDisconnected)
+
+	monitor.Stop()
+	if _, ok := <-events; ok {
+		t.Error("expected channel to be closed after Stop")
+	}
+}
+
+func TestHealthMonitorFailedReconnectCheck(t *testing.T) {
+	backend := &healthServer{connected: false}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	session := NewSession(client)
+	if _, err := session.Connect(ConnectParams{User: 1001, Password: "secret"}); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	monitor := NewHealthMonitor(client, HealthConfig{FailureThreshold: 2, Session: session})
+	var states []ConnectionState
+	monitor.OnStateChange(func(c StateChange) { states = append(states, c.To) })
+
+	for i := 0; i < 2; i++ {
+		if err := monitor.Check(context.Background()); err == nil {
+			t.Fatal("expected check to fail")
+		}
+	}
+
+	// re-login สำเร็จแต่ terminal ยังไม่ต่อ ต้องไม่กลับไปเป็นสถานะที่เทรดได้
+	want := []ConnectionState{StateFailing, StateDisconnected, StateReconnecting, StateDisconnected}
+	if len(states) != len(want) {
+		t.Fatalf("expected states %v, got %v", want, states)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("expected states %v, got %v", want, states)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package mt5client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // HistoryService จัดการประวัติ
 type HistoryService struct {
@@ -9,12 +12,21 @@ type HistoryService struct {
 
 // GetOrders ดึงประวัติคำสั่งซื้อขาย
 func (r *HistoryService) GetOrders(from, to string) ([]Order, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetOrdersCtx(context.Background(), from, to)
+}
+
+// GetOrdersCtx ดึงประวัติคำสั่งซื้อขาย (รองรับ context)
+func (r *HistoryService) GetOrdersCtx(ctx context.Context, from, to string) ([]Order, error) {
+	ctx, span := r.client.startSpan(ctx, "HistoryService.GetOrders", Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":   r.client.token,
+		"id":   token,
 		"from": from,
 		"to":   to,
 	}
@@ -24,7 +36,7 @@ func (r *HistoryService) GetOrders(from, to string) ([]Order, error) {
 	}
 	var response OrdersResponse
 
-	err := r.client.get("/OrderHistory", queryParams, &response)
+	err := r.client.get(ctx, "/OrderHistory", queryParams, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -34,12 +46,21 @@ func (r *HistoryService) GetOrders(from, to string) ([]Order, error) {
 
 // GetOrdersPagination ดึงประวัติคำสั่งแบบแบ่งหน้า
 func (r *HistoryService) GetOrdersPagination(from, to string, page, pageSize int) ([]Order, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetOrdersPaginationCtx(context.Background(), from, to, page, pageSize)
+}
+
+// GetOrdersPaginationCtx ดึงประวัติคำสั่งแบบแบ่งหน้า (รองรับ context)
+func (r *HistoryService) GetOrdersPaginationCtx(ctx context.Context, from, to string, page, pageSize int) ([]Order, error) {
+	ctx, span := r.client.startSpan(ctx, "HistoryService.GetOrdersPagination", Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":       r.client.token,
+		"id":       token,
 		"from":     from,
 		"to":       to,
 		"page":     fmt.Sprintf("%d", page),
@@ -47,7 +68,7 @@ func (r *HistoryService) GetOrdersPagination(from, to string, page, pageSize int
 	}
 
 	var orders []Order
-	err := r.client.get("/OrderHistoryPagination", queryParams, &orders)
+	err := r.client.get(ctx, "/OrderHistoryPagination", queryParams, &orders)
 	if err != nil {
 		return nil, err
 	}
@@ -57,16 +78,25 @@ func (r *HistoryService) GetOrdersPagination(from, to string, page, pageSize int
 
 // IsOrderHistoryDownloadComplete ตรวจสอบว่าดาวน์โหลดประวัติเสร็จหรือไม่
 func (r *HistoryService) IsOrderHistoryDownloadComplete() (bool, error) {
-	if r.client.token == "" {
-		return false, fmt.Errorf("not connected")
+	return r.IsOrderHistoryDownloadCompleteCtx(context.Background())
+}
+
+// IsOrderHistoryDownloadCompleteCtx ตรวจสอบว่าดาวน์โหลดประวัติเสร็จหรือไม่ (รองรับ context)
+func (r *HistoryService) IsOrderHistoryDownloadCompleteCtx(ctx context.Context) (bool, error) {
+	ctx, span := r.client.startSpan(ctx, "HistoryService.IsOrderHistoryDownloadComplete")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return false, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var result bool
-	err := r.client.get("/OrderHistoryDownloadComplete", queryParams, &result)
+	err := r.client.get(ctx, "/OrderHistoryDownloadComplete", queryParams, &result)
 	if err != nil {
 		return false, err
 	}
@@ -76,18 +106,27 @@ func (r *HistoryService) IsOrderHistoryDownloadComplete() (bool, error) {
 
 // GetPositions ดึงตำแหน่งในประวัติ
 func (r *HistoryService) GetPositions(from, to string) ([]HistoryPosition, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetPositionsCtx(context.Background(), from, to)
+}
+
+// GetPositionsCtx ดึงตำแหน่งในประวัติ (รองรับ context)
+func (r *HistoryService) GetPositionsCtx(ctx context.Context, from, to string) ([]HistoryPosition, error) {
+	ctx, span := r.client.startSpan(ctx, "HistoryService.GetPositions", Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":   r.client.token,
+		"id":   token,
 		"from": from,
 		"to":   to,
 	}
 
 	var positions []HistoryPosition
-	err := r.client.get("/HistoryPositions", queryParams, &positions)
+	err := r.client.get(ctx, "/HistoryPositions", queryParams, &positions)
 	if err != nil {
 		return nil, err
 	}
@@ -97,18 +136,27 @@ func (r *HistoryService) GetPositions(from, to string) ([]HistoryPosition, error
 
 // GetPositionsByCloseTime ดึงตำแหน่งตามเวลาปิด
 func (r *HistoryService) GetPositionsByCloseTime(from, to string) ([]HistoryPosition, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetPositionsByCloseTimeCtx(context.Background(), from, to)
+}
+
+// GetPositionsByCloseTimeCtx ดึงตำแหน่งตามเวลาปิด (รองรับ context)
+func (r *HistoryService) GetPositionsByCloseTimeCtx(ctx context.Context, from, to string) ([]HistoryPosition, error) {
+	ctx, span := r.client.startSpan(ctx, "HistoryService.GetPositionsByCloseTime", Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":   r.client.token,
+		"id":   token,
 		"from": from,
 		"to":   to,
 	}
 
 	var positions []HistoryPosition
-	err := r.client.get("/HistoryPositionsByCloseTime", queryParams, &positions)
+	err := r.client.get(ctx, "/HistoryPositionsByCloseTime", queryParams, &positions)
 	if err != nil {
 		return nil, err
 	}
@@ -118,20 +166,57 @@ func (r *HistoryService) GetPositionsByCloseTime(from, to string) ([]HistoryPosi
 
 // GetDealsByPositionId ดึงดีลตาม Position ID
 func (r *HistoryService) GetDealsByPositionId(positionId int64) ([]Deal, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetDealsByPositionIdCtx(context.Background(), positionId)
+}
+
+// GetDealsByPositionIdCtx ดึงดีลตาม Position ID (รองรับ context)
+func (r *HistoryService) GetDealsByPositionIdCtx(ctx context.Context, positionId int64) ([]Deal, error) {
+	ctx, span := r.client.startSpan(ctx, "HistoryService.GetDealsByPositionId", Attr("positionId", positionId))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":         r.client.token,
+		"id":         token,
 		"positionId": fmt.Sprintf("%d", positionId),
 	}
 
 	var deals []Deal
-	err := r.client.get("/HistoryDealsByPositionId", queryParams, &deals)
+	err := r.client.get(ctx, "/HistoryDealsByPositionId", queryParams, &deals)
 	if err != nil {
 		return nil, err
 	}
 
 	return deals, nil
 }
+
+// GetOrdersFiltered ดึงประวัติคำสั่งเฉพาะที่ตรงกับ filter
+func (r *HistoryService) GetOrdersFiltered(from, to string, filter OrderFilter) ([]Order, error) {
+	return r.GetOrdersFilteredCtx(context.Background(), from, to, filter)
+}
+
+// GetOrdersFilteredCtx ดึงประวัติคำสั่งเฉพาะที่ตรงกับ filter (รองรับ context)
+func (r *HistoryService) GetOrdersFilteredCtx(ctx context.Context, from, to string, filter OrderFilter) ([]Order, error) {
+	orders, err := r.GetOrdersCtx(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return FilterOrders(orders, filter), nil
+}
+
+// GetPositionsFiltered ดึงตำแหน่งในประวัติเฉพาะที่ตรงกับ filter
+func (r *HistoryService) GetPositionsFiltered(from, to string, filter OrderFilter) ([]HistoryPosition, error) {
+	return r.GetPositionsFilteredCtx(context.Background(), from, to, filter)
+}
+
+// GetPositionsFilteredCtx ดึงตำแหน่งในประวัติเฉพาะที่ตรงกับ filter (รองรับ context)
+func (r *HistoryService) GetPositionsFilteredCtx(ctx context.Context, from, to string, filter OrderFilter) ([]HistoryPosition, error) {
+	positions, err := r.GetPositionsCtx(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return FilterPositions(positions, filter), nil
+}
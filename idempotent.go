@@ -0,0 +1,264 @@
+package mt5client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxCommentLength ความยาว comment สูงสุดที่ MT5 เก็บได้
+	maxCommentLength = 31
+	// clientOrderIDMarker ตัวคั่นระหว่าง comment ของผู้ใช้กับ client order ID
+	clientOrderIDMarker = "#"
+	// maxClientOrderIDLength ความยาว client order ID สูงสุด (เหลือที่ให้ comment ของผู้ใช้)
+	maxClientOrderIDLength = 16
+	// reconcileTimeout เวลาสูงสุดในการตรวจสอบคำสั่งเมื่อ ctx ของผู้เรียกหมดเวลาไปแล้ว
+	reconcileTimeout = 10 * time.Second
+)
+
+// IdempotencyConfig ค่าตั้งของ SendIdempotent
+type IdempotencyConfig struct {
+	ReconcileDelay time.Duration // ระยะรอก่อนค้นหาคำสั่งหลังล้มเหลวแบบไม่ชัดเจน (ให้ broker ประมวลผลคำสั่งเดิมให้เสร็จ)
+	MaxAttempts    int           // จำนวนครั้งส่งสูงสุดรวมครั้งแรก (1 = ไม่ส่งซ้ำ)
+	// ResendAfterTimeout ส่งซ้ำหลัง timeout ได้ถ้าค้นไม่พบคำสั่ง (default: false)
+	// หลัง timeout broker อาจยังประมวลผลคำสั่งเดิมอยู่ การส่งซ้ำจึงเสี่ยงเปิด position ซ้ำ
+	ResendAfterTimeout bool
+}
+
+// DefaultIdempotencyConfig ค่าตั้งเริ่มต้นของ SendIdempotent
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		ReconcileDelay: 2 * time.Second,
+		MaxAttempts:    2,
+	}
+}
+
+// WithIdempotencyConfig กำหนดค่าตั้งของ SendIdempotent
+func WithIdempotencyConfig(config IdempotencyConfig) Option {
+	return func(c *Client) {
+		c.idempotency = config
+	}
+}
+
+// NewClientOrderID สร้าง client order ID แบบสุ่ม (hex 12 ตัว)
+func NewClientOrderID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano()%1e12, 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// ClientOrderIDFromComment แยก client order ID ออกจาก comment ที่ SendIdempotent ใส่ไว้
+// (ไม่สนส่วนที่ server ต่อท้าย เช่น " [sl]") คืนค่าว่างถ้าไม่มี
+func ClientOrderIDFromComment(comment string) string {
+	i := strings.LastIndex(comment, clientOrderIDMarker)
+	if i < 0 {
+		return ""
+	}
+	id := comment[i+len(clientOrderIDMarker):]
+	if j := strings.IndexAny(id, " ["); j >= 0 {
+		id = id[:j]
+	}
+	return id
+}
+
+// stampClientOrderID ต่อ client order ID ท้าย comment
+// ถ้ารวมแล้วเกิน maxCommentLength จะคืน error แทนการตัด comment (เพื่อไม่ให้ ByCommentTag หาไม่เจอ)
+func stampClientOrderID(comment, id string) (string, error) {
+	stamped := comment + clientOrderIDMarker + id
+	if utf8.RuneCountInString(stamped) > maxCommentLength {
+		return "", fmt.Errorf("%w: comment %q with client order id %q exceeds %d characters", ErrInvalidArgument, comment, id, maxCommentLength)
+	}
+	return stamped, nil
+}
+
+// validateClientOrderID ตรวจสอบ client order ID ที่ผู้ใช้กำหนดเอง
+func validateClientOrderID(id string) error {
+	switch {
+	case len(id) > maxClientOrderIDLength:
+		return fmt.Errorf("%w: client order id %q is longer than %d characters", ErrInvalidArgument, id, maxClientOrderIDLength)
+	case strings.ContainsAny(id, " ["+clientOrderIDMarker):
+		return fmt.Errorf("%w: client order id %q must not contain spaces, '[' or %q", ErrInvalidArgument, id, clientOrderIDMarker)
+	}
+	return nil
+}
+
+// isAmbiguousSendError ตรวจสอบว่า error จาก postOrder ไม่สามารถบอกได้ว่าคำสั่งถึง server หรือไม่
+// (เช่น timeout, connection reset, 5xx, response อ่านไม่ได้) ส่วน error ที่ server ปฏิเสธชัดเจน
+// และ error ก่อนส่ง (เช่นรอ rate limit ไม่ทัน) ไม่นับ
+func isAmbiguousSendError(err error) bool {
+	var waitErr *limitWaitError
+	if errors.As(err, &waitErr) {
+		return false
+	}
+
+	switch {
+	case errors.Is(err, ErrInvalidArgument),
+		errors.Is(err, ErrRequestRejected),
+		errors.Is(err, ErrNotConnected),
+		errors.Is(err, ErrSessionExpired),
+		errors.Is(err, ErrSymbolNotFound):
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// isTimeoutError ตรวจสอบว่าคำสั่งล้มเหลวเพราะ timeout (ฝั่ง client หรือ gateway)
+func isTimeoutError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusGatewayTimeout
+}
+
+// SendIdempotent ส่งคำสั่งโดยป้องกันการเปิด position ซ้ำ
+//
+// ใส่ client order ID (req.ClientOrderID หรือสร้างใหม่) ไว้ท้าย comment แล้วส่ง /OrderSend
+// ถ้าล้มเหลวแบบไม่ชัดเจน (timeout, 5xx ฯลฯ) จะรอ IdempotencyConfig.ReconcileDelay แล้วค้นคำสั่งที่มี ID นี้
+// จาก OrderService.GetOpened และ HistoryService.GetOrders ถ้าพบจะคืนคำสั่งนั้น
+// ถ้าไม่พบจึงส่งใหม่ (ไม่เกิน IdempotencyConfig.MaxAttempts) ยกเว้นหลัง timeout ที่ไม่ส่งซ้ำโดย default
+// กรณีที่ตรวจสอบไม่ได้หรือไม่ส่งซ้ำหลัง timeout จะคืน error ที่ errors.Is(err, ErrOrderStateUnknown) เป็นจริง
+// ผู้เรียกตรวจสอบภายหลังได้ด้วย OrderService.FindByClientOrderID
+func (r *TradingService) SendIdempotent(req OrderRequest) (*Order, error) {
+	return r.SendIdempotentCtx(context.Background(), req)
+}
+
+// SendIdempotentCtx ส่งคำสั่งโดยป้องกันการเปิด position ซ้ำ (รองรับ context)
+func (r *TradingService) SendIdempotentCtx(ctx context.Context, req OrderRequest) (*Order, error) {
+	ctx, span := r.client.startSpan(ctx, "TradingService.SendIdempotent", Attr("symbol", req.Symbol), Attr("type", req.Type), Attr("volume", req.Volume))
+	defer span.End()
+
+	return r.sendIdempotent(ctx, req)
+}
+
+// sendIdempotent ส่งคำสั่งพร้อม reconcile ตาม client order ID
+func (r *TradingService) sendIdempotent(ctx context.Context, req OrderRequest) (*Order, error) {
+	if req.ClientOrderID == "" {
+		req.ClientOrderID = NewClientOrderID()
+	} else if err := validateClientOrderID(req.ClientOrderID); err != nil {
+		return nil, err
+	}
+	id := req.ClientOrderID
+	comment, err := stampClientOrderID(req.Comment, id)
+	if err != nil {
+		return nil, err
+	}
+	req.Comment = comment
+
+	if r.client.GetToken() == "" {
+		return nil, ErrNotConnected
+	}
+	// ตรวจสอบครั้งเดียวก่อนส่ง error ตรงนี้แปลว่ายังไม่ได้ส่งคำสั่ง จึงไม่ต้อง reconcile
+	queryParams, err := r.prepareOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	config := r.client.idempotency
+	maxAttempts := config.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		order, err := r.postOrder(ctx, queryParams)
+		if err == nil || !isAmbiguousSendError(err) {
+			return order, err
+		}
+
+		r.client.logger.LogAttrs(ctx, slog.LevelWarn, "order send result unknown, reconciling",
+			slog.String("clientOrderId", id),
+			slog.Int("attempt", attempt),
+			slog.Any("error", err),
+		)
+
+		// รอให้ server ประมวลผลคำสั่งที่อาจค้างอยู่ก่อนค้นหา
+		_ = sleepContext(ctx, config.ReconcileDelay)
+
+		found, lookupErr := r.reconcile(ctx, id)
+		if lookupErr != nil {
+			return nil, fmt.Errorf("%w (client order %s): %w", ErrOrderStateUnknown, id, errors.Join(err, lookupErr))
+		}
+		if found != nil {
+			return found, nil
+		}
+		if isTimeoutError(err) && !config.ResendAfterTimeout {
+			// ไม่พบยังไม่ได้แปลว่าไม่ได้วาง broker อาจยัง fill คำสั่งเดิมอยู่
+			return nil, fmt.Errorf("%w (client order %s not found after timeout): %w", ErrOrderStateUnknown, id, err)
+		}
+		if attempt >= maxAttempts || ctx.Err() != nil {
+			return nil, err
+		}
+	}
+}
+
+// reconcile ค้นคำสั่งตาม client order ID (ใช้ context แยกถ้า ctx ของผู้เรียกหมดเวลาแล้ว)
+func (r *TradingService) reconcile(ctx context.Context, id string) (*Order, error) {
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), reconcileTimeout)
+		defer cancel()
+	}
+	return r.client.Order.FindByClientOrderIDCtx(ctx, id)
+}
+
+// FindByClientOrderID ค้นคำสั่งที่ส่งด้วย SendIdempotent ตาม client order ID
+// จากคำสั่งที่เปิดอยู่และประวัติคำสั่งย้อนหลัง 1 วัน คืน nil ถ้าไม่พบ
+func (r *OrderService) FindByClientOrderID(id string) (*Order, error) {
+	return r.FindByClientOrderIDCtx(context.Background(), id)
+}
+
+// FindByClientOrderIDCtx ค้นคำสั่งตาม client order ID (รองรับ context)
+func (r *OrderService) FindByClientOrderIDCtx(ctx context.Context, id string) (*Order, error) {
+	ctx, span := r.client.startSpan(ctx, "OrderService.FindByClientOrderID", Attr("clientOrderId", id))
+	defer span.End()
+
+	opened, err := r.GetOpenedCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if order := findClientOrder(opened, id); order != nil {
+		return order, nil
+	}
+
+	// คำสั่งอาจถูกปิดไปแล้ว (เช่นโดน SL) เผื่อเวลาทั้งสองฝั่งเพราะเวลาของ server อาจต่างจาก local
+	now := time.Now()
+	from := now.Add(-24 * time.Hour).Format("2006-01-02T15:04:05")
+	to := now.Add(24 * time.Hour).Format("2006-01-02T15:04:05")
+	history, err := r.client.History.GetOrdersCtx(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return findClientOrder(history, id), nil
+}
+
+// findClientOrder คืนคำสั่งแรกที่มี client order ID ตรงกัน
+func findClientOrder(orders []Order, id string) *Order {
+	for i := range orders {
+		if ClientOrderIDFromComment(orders[i].Comment) == id {
+			return &orders[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package mt5client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ditthkr/mt5client"
+	"github.com/ditthkr/mt5client/mt5test"
+)
+
+// newIdempotentGateway gateway สำหรับทดสอบ SendIdempotent (ไม่ retry ระดับ HTTP, reconcile เร็ว, ส่งได้ 3 ครั้ง)
+func newIdempotentGateway(t *testing.T, opts ...mt5client.Option) (*mt5test.Server, *mt5client.Client) {
+	t.Helper()
+
+	config := mt5client.DefaultIdempotencyConfig()
+	config.ReconcileDelay = time.Millisecond
+	config.MaxAttempts = 3
+
+	return newGateway(t, append([]mt5client.Option{
+		mt5client.WithRetryPolicy(mt5client.NoRetryPolicy()),
+		mt5client.WithIdempotencyConfig(config),
+	}, opts...)...)
+}
+
+func TestSendIdempotentReconcilesPlacedOrder(t *testing.T) {
+	srv, client := newIdempotentGateway(t)
+	srv.FailNext("/OrderSend", mt5test.Failure{StatusCode: http.StatusGatewayTimeout, Applied: true})
+
+	order, err := client.Trading.SendIdempotent(mt5client.OrderRequest{Symbol: "EURUSD", Type: mt5client.OrderBuy, Volume: 0.1, Comment: "grid", ClientOrderID: "abc123"})
+	if err != nil {
+		t.Fatalf("SendIdempotent failed: %v", err)
+	}
+	if order.Comment != "grid#abc123" {
+		t.Errorf("unexpected order %+v", order)
+	}
+	if n := srv.RequestCount("/OrderSend"); n != 1 {
+		t.Errorf("expected no resend, got %d sends", n)
+	}
+}
+
+func TestSendIdempotentResendsWhenNotPlaced(t *testing.T) {
+	srv, client := newIdempotentGateway(t, mt5client.WithIdempotentOrders())
+	srv.FailNext("/OrderSend", mt5test.Failure{StatusCode: http.StatusBadGateway})
+
+	order, err := client.Trading.Send(mt5client.OrderRequest{Symbol: "EURUSD", Type: mt5client.OrderBuy, Volume: 0.1})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if n := srv.RequestCount("/OrderSend"); n != 2 {
+		t.Fatalf("expected 2 sends, got %d", n)
+	}
+	if opened := srv.OpenedOrders(testLogin); len(opened) != 1 {
+		t.Fatalf("expected 1 opened order, got %d", len(opened))
+	}
+	if id := mt5client.ClientOrderIDFromComment(order.Comment); len(id) != 12 {
+		t.Errorf("expected generated client order id in comment, got %q", order.Comment)
+	}
+}
+
+func TestSendIdempotentFindsClosedOrder(t *testing.T) {
+	srv, client := newIdempotentGateway(t)
+	srv.FailNext("/OrderSend", mt5test.Failure{StatusCode: http.StatusGatewayTimeout})
+	srv.AddClosedOrder(testLogin, mt5client.Order{Ticket: 9, Symbol: "EURUSD", Comment: "grid#abc123 [sl]"})
+
+	order, err := client.Trading.SendIdempotent(mt5client.OrderRequest{Symbol: "EURUSD", Type: mt5client.OrderBuy, Volume: 0.1, Comment: "grid", ClientOrderID: "abc123"})
+	if err != nil {
+		t.Fatalf("SendIdempotent failed: %v", err)
+	}
+	if order.Ticket != 9 {
+		t.Errorf("expected closed order 9, got %+v", order)
+	}
+}
+
+func TestSendIdempotentErrors(t *testing.T) {
+	buy := mt5client.OrderRequest{Symbol: "EURUSD", Type: mt5client.OrderBuy, Volume: 0.1}
+
+	t.Run("rejected is not reconciled", func(t *testing.T) {
+		srv, client := newIdempotentGateway(t)
+		srv.FailNext("/OrderSend", mt5test.Failure{StatusCode: http.StatusBadRequest, Body: `{"message":"failure"}`})
+
+		_, err := client.Trading.SendIdempotent(buy)
+		if err == nil || errors.Is(err, mt5client.ErrOrderStateUnknown) {
+			t.Fatalf("expected plain API error, got %v", err)
+		}
+		if n := srv.RequestCount("/OrderSend"); n != 1 {
+			t.Errorf("expected 1 send, got %d", n)
+		}
+		if n := srv.RequestCount("/OpenedOrders"); n != 0 {
+			t.Errorf("expected no reconcile, got %d lookups", n)
+		}
+	})
+
+	t.Run("failed lookup does not resend", func(t *testing.T) {
+		srv, client := newIdempotentGateway(t)
+		srv.FailNext("/OrderSend", mt5test.Failure{StatusCode: http.StatusGatewayTimeout})
+		srv.FailNext("/OpenedOrders", mt5test.Failure{StatusCode: http.StatusServiceUnavailable})
+
+		_, err := client.Trading.SendIdempotent(buy)
+		if !errors.Is(err, mt5client.ErrOrderStateUnknown) || !errors.Is(err, mt5client.ErrServer) {
+			t.Fatalf("expected ErrOrderStateUnknown wrapping server error, got %v", err)
+		}
+		if n := srv.RequestCount("/OrderSend"); n != 1 {
+			t.Errorf("expected 1 send, got %d", n)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		srv, client := newIdempotentGateway(t)
+		failure := mt5test.Failure{StatusCode: http.StatusBadGateway}
+		srv.FailNext("/OrderSend", failure, failure, failure)
+
+		_, err := client.Trading.SendIdempotent(buy)
+		if !errors.Is(err, mt5client.ErrServer) {
+			t.Fatalf("expected server error, got %v", err)
+		}
+		if n := srv.RequestCount("/OrderSend"); n != 3 {
+			t.Errorf("expected 3 sends, got %d", n)
+		}
+	})
+
+	t.Run("timeout is not resent by default", func(t *testing.T) {
+		srv, client := newIdempotentGateway(t)
+		srv.FailNext("/OrderSend", mt5test.Failure{StatusCode: http.StatusGatewayTimeout})
+
+		_, err := client.Trading.SendIdempotent(buy)
+		if !errors.Is(err, mt5client.ErrOrderStateUnknown) {
+			t.Fatalf("expected ErrOrderStateUnknown, got %v", err)
+		}
+		if n := srv.RequestCount("/OrderSend"); n != 1 {
+			t.Errorf("expected 1 send, got %d", n)
+		}
+	})
+
+	t.Run("timeout resent when enabled", func(t *testing.T) {
+		srv, client := newIdempotentGateway(t, mt5client.WithIdempotencyConfig(mt5client.IdempotencyConfig{
+			ReconcileDelay:     time.Millisecond,
+			MaxAttempts:        2,
+			ResendAfterTimeout: true,
+		}))
+		srv.FailNext("/OrderSend", mt5test.Failure{StatusCode: http.StatusGatewayTimeout})
+
+		if _, err := client.Trading.SendIdempotent(buy); err != nil {
+			t.Fatalf("SendIdempotent failed: %v", err)
+		}
+		if n := srv.RequestCount("/OrderSend"); n != 2 {
+			t.Errorf("expected 2 sends, got %d", n)
+		}
+	})
+
+	t.Run("invalid client order id", func(t *testing.T) {
+		_, client := newIdempotentGateway(t)
+
+		req := buy
+		req.ClientOrderID = "has space"
+		if _, err := client.Trading.SendIdempotent(req); !errors.Is(err, mt5client.ErrInvalidArgument) {
+			t.Fatalf("expected ErrInvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("comment too long for client order id", func(t *testing.T) {
+		srv, client := newIdempotentGateway(t)
+
+		req := buy
+		req.Comment = "a very long strategy comment here"
+		if _, err := client.Trading.SendIdempotent(req); !errors.Is(err, mt5client.ErrInvalidArgument) {
+			t.Fatalf("expected ErrInvalidArgument, got %v", err)
+		}
+		if n := srv.RequestCount("/OrderSend"); n != 0 {
+			t.Errorf("expected no send, got %d", n)
+		}
+	})
+}
+
+func TestSendIdempotentPreSendFailures(t *testing.T) {
+	buy := mt5client.OrderRequest{Symbol: "EURUSD", Type: mt5client.OrderBuy, Volume: 0.1}
+
+	for _, status := range []int{http.StatusGatewayTimeout, http.StatusBadGateway} {
+		t.Run(fmt.Sprintf("symbol params %d", status), func(t *testing.T) {
+			srv, client := newIdempotentGateway(t, mt5client.WithOrderValidation(), mt5client.WithIdempotentOrders())
+			srv.FailNext("/SymbolParams", mt5test.Failure{StatusCode: status})
+
+			_, err := client.Trading.Buy("EURUSD", 0.1, 0, 0)
+			if !errors.Is(err, mt5client.ErrServer) || errors.Is(err, mt5client.ErrOrderStateUnknown) {
+				t.Fatalf("expected plain server error, got %v", err)
+			}
+			if n := srv.RequestCount("/OrderSend"); n != 0 {
+				t.Errorf("expected no send, got %d", n)
+			}
+			if n := srv.RequestCount("/OpenedOrders"); n != 0 {
+				t.Errorf("expected no reconcile, got %d lookups", n)
+			}
+		})
+	}
+
+	t.Run("rate limit wait", func(t *testing.T) {
+		srv, client := newIdempotentGateway(t, mt5client.WithGroupRateLimit(mt5client.EndpointGroupTrading, 0.001, 1))
+		if _, err := client.Trading.SendIdempotent(buy); err != nil {
+			t.Fatalf("SendIdempotent failed: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := client.Trading.SendIdempotentCtx(ctx, buy)
+		if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, mt5client.ErrOrderStateUnknown) {
+			t.Fatalf("expected context deadline, got %v", err)
+		}
+		if n := srv.RequestCount("/OrderSend"); n != 1 {
+			t.Errorf("expected only the first send, got %d", n)
+		}
+		if n := srv.RequestCount("/OpenedOrders"); n != 0 {
+			t.Errorf("expected no reconcile, got %d lookups", n)
+		}
+	})
+}
+
+func TestClientOrderIDFromComment(t *testing.T) {
+	tests := map[string]string{
+		"grid#0123456789ab":      "0123456789ab",
+		"grid#0123456789ab [tp]": "0123456789ab",
+		"#abc":                   "abc",
+		"grid":                   "",
+	}
+	for comment, want := range tests {
+		if got := mt5client.ClientOrderIDFromComment(comment); got != want {
+			t.Errorf("ClientOrderIDFromComment(%q) = %q, want %q", comment, got, want)
+		}
+	}
+}
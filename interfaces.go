@@ -0,0 +1,329 @@
+package mt5client
+
+import "context"
+
+// Connector การเชื่อมต่อ/ตัดการเชื่อมต่อ MT5 (ConnectionService เป็น implementation หลัก)
+type Connector interface {
+	Connect(params ConnectParams) (string, error)
+	ConnectCtx(ctx context.Context, params ConnectParams) (string, error)
+	ConnectEx(user int64, password, server string) (string, error)
+	ConnectExCtx(ctx context.Context, user int64, password, server string) (string, error)
+	ConnectProxy(params ConnectParams, proxyType, proxyHost string, proxyPort int) (string, error)
+	ConnectProxyCtx(ctx context.Context, params ConnectParams, proxyType, proxyHost string, proxyPort int) (string, error)
+	ConnectViaProxy(params ConnectParams, proxy ProxyConfig) (string, error)
+	ConnectViaProxyCtx(ctx context.Context, params ConnectParams, proxy ProxyConfig) (string, error)
+	ConnectWith(provider CredentialProvider) (string, error)
+	ConnectWithCtx(ctx context.Context, provider CredentialProvider) (string, error)
+	ConnectFailover(user int64, password string, candidates []ServerCandidate) (*FailoverResult, error)
+	ConnectFailoverCtx(ctx context.Context, user int64, password string, candidates []ServerCandidate) (*FailoverResult, error)
+	Disconnect() error
+	DisconnectCtx(ctx context.Context) error
+	IsConnected() (bool, error)
+	IsConnectedCtx(ctx context.Context) (bool, error)
+}
+
+// AccountReader อ่านข้อมูลบัญชี (AccountService เป็น implementation หลัก)
+type AccountReader interface {
+	GetInfo() (*Account, error)
+	GetInfoCtx(ctx context.Context) (*Account, error)
+	GetDetails() (*Account, error)
+	GetDetailsCtx(ctx context.Context) (*Account, error)
+	GetSummary() (map[string]interface{}, error)
+	GetSummaryCtx(ctx context.Context) (map[string]interface{}, error)
+	GetEquityHistory(from, to string) ([]map[string]interface{}, error)
+	GetEquityHistoryCtx(ctx context.Context, from, to string) ([]map[string]interface{}, error)
+}
+
+// Trader ส่ง/แก้ไข/ปิดคำสั่งซื้อขาย (TradingService เป็น implementation หลัก)
+type Trader interface {
+	Send(req OrderRequest) (*Order, error)
+	SendCtx(ctx context.Context, req OrderRequest) (*Order, error)
+	SendIdempotent(req OrderRequest) (*Order, error)
+	SendIdempotentCtx(ctx context.Context, req OrderRequest) (*Order, error)
+	Buy(symbol string, volume float64, sl, tp float64) (*Order, error)
+	BuyCtx(ctx context.Context, symbol string, volume float64, sl, tp float64) (*Order, error)
+	Sell(symbol string, volume float64, sl, tp float64) (*Order, error)
+	SellCtx(ctx context.Context, symbol string, volume float64, sl, tp float64) (*Order, error)
+	BuyLimit(order PendingOrder) (*Order, error)
+	BuyLimitCtx(ctx context.Context, order PendingOrder) (*Order, error)
+	SellLimit(order PendingOrder) (*Order, error)
+	SellLimitCtx(ctx context.Context, order PendingOrder) (*Order, error)
+	BuyStop(order PendingOrder) (*Order, error)
+	BuyStopCtx(ctx context.Context, order PendingOrder) (*Order, error)
+	SellStop(order PendingOrder) (*Order, error)
+	SellStopCtx(ctx context.Context, order PendingOrder) (*Order, error)
+	BuyStopLimit(order PendingOrder) (*Order, error)
+	BuyStopLimitCtx(ctx context.Context, order PendingOrder) (*Order, error)
+	SellStopLimit(order PendingOrder) (*Order, error)
+	SellStopLimitCtx(ctx context.Context, order PendingOrder) (*Order, error)
+	Modify(ticket int64, price, sl, tp float64) error
+	ModifyCtx(ctx context.Context, ticket int64, price, sl, tp float64) error
+	Close(ticket int64, volume float64) error
+	CloseCtx(ctx context.Context, ticket int64, volume float64) error
+}
+
+// OrderReader อ่านคำสั่งที่เปิดอยู่และที่ปิดแล้ว (OrderService เป็น implementation หลัก)
+type OrderReader interface {
+	GetOpened() ([]Order, error)
+	GetOpenedCtx(ctx context.Context) ([]Order, error)
+	GetOpenedByTicket(ticket int64) (*Order, error)
+	GetOpenedByTicketCtx(ctx context.Context, ticket int64) (*Order, error)
+	GetOpenedTickets() ([]int64, error)
+	GetOpenedTicketsCtx(ctx context.Context) ([]int64, error)
+	GetClosed(from, to string) ([]Order, error)
+	GetClosedCtx(ctx context.Context, from, to string) ([]Order, error)
+	GetPendingHistory(from, to string) ([]Order, error)
+	GetPendingHistoryCtx(ctx context.Context, from, to string) ([]Order, error)
+	GetOpenedFiltered(filter OrderFilter) ([]Order, error)
+	GetOpenedFilteredCtx(ctx context.Context, filter OrderFilter) ([]Order, error)
+	GetClosedFiltered(from, to string, filter OrderFilter) ([]Order, error)
+	GetClosedFilteredCtx(ctx context.Context, from, to string, filter OrderFilter) ([]Order, error)
+	FindByClientOrderID(id string) (*Order, error)
+	FindByClientOrderIDCtx(ctx context.Context, id string) (*Order, error)
+}
+
+// HistoryReader อ่านประวัติคำสั่ง ตำแหน่ง และดีล (HistoryService เป็น implementation หลัก)
+type HistoryReader interface {
+	GetOrders(from, to string) ([]Order, error)
+	GetOrdersCtx(ctx context.Context, from, to string) ([]Order, error)
+	GetOrdersPagination(from, to string, page, pageSize int) ([]Order, error)
+	GetOrdersPaginationCtx(ctx context.Context, from, to string, page, pageSize int) ([]Order, error)
+	IsOrderHistoryDownloadComplete() (bool, error)
+	IsOrderHistoryDownloadCompleteCtx(ctx context.Context) (bool, error)
+	GetPositions(from, to string) ([]HistoryPosition, error)
+	GetPositionsCtx(ctx context.Context, from, to string) ([]HistoryPosition, error)
+	GetPositionsByCloseTime(from, to string) ([]HistoryPosition, error)
+	GetPositionsByCloseTimeCtx(ctx context.Context, from, to string) ([]HistoryPosition, error)
+	GetDealsByPositionId(positionId int64) ([]Deal, error)
+	GetDealsByPositionIdCtx(ctx context.Context, positionId int64) ([]Deal, error)
+	GetOrdersFiltered(from, to string, filter OrderFilter) ([]Order, error)
+	GetOrdersFilteredCtx(ctx context.Context, from, to string, filter OrderFilter) ([]Order, error)
+	GetPositionsFiltered(from, to string, filter OrderFilter) ([]HistoryPosition, error)
+	GetPositionsFilteredCtx(ctx context.Context, from, to string, filter OrderFilter) ([]HistoryPosition, error)
+}
+
+// QuoteProvider ดึงราคาและสถานะเซสชัน (QuoteService เป็น implementation หลัก)
+type QuoteProvider interface {
+	Get(symbol string) (*Quote, error)
+	GetCtx(ctx context.Context, symbol string) (*Quote, error)
+	GetMany(symbols []string) ([]Quote, error)
+	GetManyCtx(ctx context.Context, symbols []string) ([]Quote, error)
+	GetTickValueMany(symbols []string) (map[string]float64, error)
+	GetTickValueManyCtx(ctx context.Context, symbols []string) (map[string]float64, error)
+	GetTickValueWithSize(symbol string, volume float64) (float64, error)
+	GetTickValueWithSizeCtx(ctx context.Context, symbol string, volume float64) (float64, error)
+	IsQuoteSession(symbol string) (bool, error)
+	IsQuoteSessionCtx(ctx context.Context, symbol string) (bool, error)
+	IsQuoteSessionMany(symbols []string) (map[string]bool, error)
+	IsQuoteSessionManyCtx(ctx context.Context, symbols []string) (map[string]bool, error)
+	IsTradeSession(symbol string) (bool, error)
+	IsTradeSessionCtx(ctx context.Context, symbol string) (bool, error)
+	IsTradeSessionMany(symbols []string) (map[string]bool, error)
+	IsTradeSessionManyCtx(ctx context.Context, symbols []string) (map[string]bool, error)
+}
+
+// SymbolProvider ดึงข้อมูลสัญลักษณ์ (SymbolService เป็น implementation หลัก)
+type SymbolProvider interface {
+	GetList() ([]string, error)
+	GetListCtx(ctx context.Context) ([]string, error)
+	GetParams(symbol string) (*SymbolParams, error)
+	GetParamsCtx(ctx context.Context, symbol string) (*SymbolParams, error)
+	GetParamsMany(symbols []string) ([]SymbolParams, error)
+	GetParamsManyCtx(ctx context.Context, symbols []string) ([]SymbolParams, error)
+	GetSessions(symbol string) (map[string]interface{}, error)
+	GetSessionsCtx(ctx context.Context, symbol string) (map[string]interface{}, error)
+	GetAll() ([]SymbolInfo, error)
+	GetAllCtx(ctx context.Context) ([]SymbolInfo, error)
+	GetSubscribed() ([]string, error)
+	GetSubscribedCtx(ctx context.Context) ([]string, error)
+}
+
+// PriceHistoryProvider ดึงประวัติราคา (PriceService เป็น implementation หลัก)
+type PriceHistoryProvider interface {
+	GetHistory(symbol, timeframe string, count int) ([]Bar, error)
+	GetHistoryCtx(ctx context.Context, symbol, timeframe string, count int) ([]Bar, error)
+	GetHistoryEx(symbol, timeframe, from, to string) ([]Bar, error)
+	GetHistoryExCtx(ctx context.Context, symbol, timeframe, from, to string) ([]Bar, error)
+	GetHistoryMany(symbols []string, timeframe string, count int) (map[string][]Bar, error)
+	GetHistoryManyCtx(ctx context.Context, symbols []string, timeframe string, count int) (map[string][]Bar, error)
+	GetHistoryExMany(symbols []string, timeframe, from, to string) (map[string][]Bar, error)
+	GetHistoryExManyCtx(ctx context.Context, symbols []string, timeframe, from, to string) (map[string][]Bar, error)
+	GetHistoryHighLow(symbol, from, to string) (map[string]float64, error)
+	GetHistoryHighLowCtx(ctx context.Context, symbol, from, to string) (map[string]float64, error)
+	GetHistoryToday(symbol, timeframe string) ([]Bar, error)
+	GetHistoryTodayCtx(ctx context.Context, symbol, timeframe string) ([]Bar, error)
+	GetHistoryTodayMany(symbols []string, timeframe string) (map[string][]Bar, error)
+	GetHistoryTodayManyCtx(ctx context.Context, symbols []string, timeframe string) (map[string][]Bar, error)
+	GetHistoryMonth(symbol, timeframe string, year, month int) ([]Bar, error)
+	GetHistoryMonthCtx(ctx context.Context, symbol, timeframe string, year, month int) ([]Bar, error)
+	GetHistoryMonthMany(symbols []string, timeframe string, year, month int) (map[string][]Bar, error)
+	GetHistoryMonthManyCtx(ctx context.Context, symbols []string, timeframe string, year, month int) (map[string][]Bar, error)
+	RequestTickHistory(symbol, from, to string) error
+	RequestTickHistoryCtx(ctx context.Context, symbol, from, to string) error
+	StopTickHistory() error
+	StopTickHistoryCtx(ctx context.Context) error
+}
+
+// StatsProvider ดึงสถิติการเทรด (StatsService เป็น implementation หลัก)
+type StatsProvider interface {
+	GetTradeStats() (*TradeStats, error)
+	GetTradeStatsCtx(ctx context.Context) (*TradeStats, error)
+	GetEquityHistory(from, to string) ([]map[string]interface{}, error)
+	GetEquityHistoryCtx(ctx context.Context, from, to string) ([]map[string]interface{}, error)
+}
+
+// Subscriber จัดการ subscription ราคา (SubscriptionService เป็น implementation หลัก)
+type Subscriber interface {
+	Subscribe(symbol string) error
+	SubscribeCtx(ctx context.Context, symbol string) error
+	SubscribeMany(symbols []string) error
+	SubscribeManyCtx(ctx context.Context, symbols []string) error
+	Unsubscribe(symbol string) error
+	UnsubscribeCtx(ctx context.Context, symbol string) error
+	UnsubscribeMany(symbols []string) error
+	UnsubscribeManyCtx(ctx context.Context, symbols []string) error
+	SubscribeTickValue(symbol string) error
+	SubscribeTickValueCtx(ctx context.Context, symbol string) error
+	SubscribeOhlc(symbol, timeframe string) error
+	SubscribeOhlcCtx(ctx context.Context, symbol, timeframe string) error
+	UnsubscribeOhlc(symbol, timeframe string) error
+	UnsubscribeOhlcCtx(ctx context.Context, symbol, timeframe string) error
+	SubscribeOrderBook(symbol string) error
+	SubscribeOrderBookCtx(ctx context.Context, symbol string) error
+	UnsubscribeOrderBook(symbol string) error
+	UnsubscribeOrderBookCtx(ctx context.Context, symbol string) error
+	SubscribeMarketWatch() error
+	SubscribeMarketWatchCtx(ctx context.Context) error
+	GetWebSocketURL() (string, error)
+}
+
+// ServiceProvider ฟังก์ชันบริการทั่วไปและการคำนวณ (ServiceFunctions เป็น implementation หลัก)
+type ServiceProvider interface {
+	GetVersion() (string, error)
+	GetVersionCtx(ctx context.Context) (string, error)
+	Ping() (string, error)
+	PingCtx(ctx context.Context) (string, error)
+	PingHost(host string) (bool, error)
+	PingHostCtx(ctx context.Context, host string) (bool, error)
+	PingHostMany(hosts []string) (map[string]bool, error)
+	PingHostManyCtx(ctx context.Context, hosts []string) (map[string]bool, error)
+	RankServers(candidates []ServerCandidate) ([]ServerRank, error)
+	RankServersCtx(ctx context.Context, candidates []ServerCandidate) ([]ServerRank, error)
+	Search(keyword string) ([]string, error)
+	SearchCtx(ctx context.Context, keyword string) ([]string, error)
+	GetServerTimezone() (string, error)
+	GetServerTimezoneCtx(ctx context.Context) (string, error)
+	GetClusterDetails() (map[string]interface{}, error)
+	GetClusterDetailsCtx(ctx context.Context) (map[string]interface{}, error)
+	ChangePassword(oldPassword, newPassword string) error
+	ChangePasswordCtx(ctx context.Context, oldPassword, newPassword string) error
+	GetDemo(server, name, email string) (map[string]interface{}, error)
+	GetDemoCtx(ctx context.Context, server, name, email string) (map[string]interface{}, error)
+	GetRequiredMargin(symbol string, volume float64) (float64, error)
+	GetRequiredMarginCtx(ctx context.Context, symbol string, volume float64) (float64, error)
+	GetMails() ([]Mail, error)
+	GetMailsCtx(ctx context.Context) ([]Mail, error)
+	GetMarketWatchMany(symbols []string) ([]MarketWatch, error)
+	GetMarketWatchManyCtx(ctx context.Context, symbols []string) ([]MarketWatch, error)
+	GetQuoteClient() (map[string]interface{}, error)
+	GetQuoteClientCtx(ctx context.Context) (map[string]interface{}, error)
+	LoadServersDat(data []byte) error
+	LoadServersDatCtx(ctx context.Context, data []byte) error
+	GetMetricsApiKey() (string, error)
+	GetMetricsApiKeyCtx(ctx context.Context) (string, error)
+	GetReadMe() (string, error)
+	GetReadMeCtx(ctx context.Context) (string, error)
+	CalculateLotSize(symbol string, entryPrice, stopLoss, riskAmount float64) (*LotSizeResult, error)
+	CalculateLotSizeCtx(ctx context.Context, symbol string, entryPrice, stopLoss, riskAmount float64) (*LotSizeResult, error)
+	CalculateLotSizeByPercent(symbol string, entryPrice, stopLoss, riskPercent float64) (*LotSizeResult, error)
+	CalculateLotSizeByPercentCtx(ctx context.Context, symbol string, entryPrice, stopLoss, riskPercent float64) (*LotSizeResult, error)
+	CalculatePipValue(symbol string, lotSize float64) (float64, error)
+	CalculatePipValueCtx(ctx context.Context, symbol string, lotSize float64) (float64, error)
+}
+
+// API interface ระดับ Client สำหรับแทนที่ด้วย fake หรือ backend อื่น
+type API interface {
+	GetToken() string
+	SetToken(token string)
+	ConnectionAPI() Connector
+	AccountAPI() AccountReader
+	TradingAPI() Trader
+	OrderAPI() OrderReader
+	HistoryAPI() HistoryReader
+	QuoteAPI() QuoteProvider
+	SymbolAPI() SymbolProvider
+	PriceAPI() PriceHistoryProvider
+	StatsAPI() StatsProvider
+	SubscriptionAPI() Subscriber
+	ServiceAPI() ServiceProvider
+}
+
+// ConnectionAPI คืน Connection ในรูป interface
+func (r *Client) ConnectionAPI() Connector {
+	return r.Connection
+}
+
+// AccountAPI คืน Account ในรูป interface
+func (r *Client) AccountAPI() AccountReader {
+	return r.Account
+}
+
+// TradingAPI คืน Trading ในรูป interface
+func (r *Client) TradingAPI() Trader {
+	return r.Trading
+}
+
+// OrderAPI คืน Order ในรูป interface
+func (r *Client) OrderAPI() OrderReader {
+	return r.Order
+}
+
+// HistoryAPI คืน History ในรูป interface
+func (r *Client) HistoryAPI() HistoryReader {
+	return r.History
+}
+
+// QuoteAPI คืน Quote ในรูป interface
+func (r *Client) QuoteAPI() QuoteProvider {
+	return r.Quote
+}
+
+// SymbolAPI คืน Symbol ในรูป interface
+func (r *Client) SymbolAPI() SymbolProvider {
+	return r.Symbol
+}
+
+// PriceAPI คืน Price ในรูป interface
+func (r *Client) PriceAPI() PriceHistoryProvider {
+	return r.Price
+}
+
+// StatsAPI คืน Stats ในรูป interface
+func (r *Client) StatsAPI() StatsProvider {
+	return r.Stats
+}
+
+// SubscriptionAPI คืน Subscription ในรูป interface
+func (r *Client) SubscriptionAPI() Subscriber {
+	return r.Subscription
+}
+
+// ServiceAPI คืน Service ในรูป interface
+func (r *Client) ServiceAPI() ServiceProvider {
+	return r.Service
+}
+
+var (
+	_ API                  = (*Client)(nil)
+	_ Connector            = (*ConnectionService)(nil)
+	_ AccountReader        = (*AccountService)(nil)
+	_ Trader               = (*TradingService)(nil)
+	_ OrderReader          = (*OrderService)(nil)
+	_ HistoryReader        = (*HistoryService)(nil)
+	_ QuoteProvider        = (*QuoteService)(nil)
+	_ SymbolProvider       = (*SymbolService)(nil)
+	_ PriceHistoryProvider = (*PriceService)(nil)
+	_ StatsProvider        = (*StatsService)(nil)
+	_ Subscriber           = (*SubscriptionService)(nil)
+	_ ServiceProvider      = (*ServiceFunctions)(nil)
+)
@@ -0,0 +1,29 @@
+package mt5client
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger กำหนด logger สำหรับ REST และ WebSocket (nil = ปิด log)
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger == nil {
+			logger = slog.New(discardHandler{})
+		}
+		c.logger = logger
+	}
+}
+
+// Logger คืน logger ที่ Client ใช้อยู่
+func (r *Client) Logger() *slog.Logger {
+	return r.logger
+}
+
+// discardHandler slog.Handler ที่ทิ้งทุก record
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,37 @@
+package mt5client
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryIsLoggedWithStructuredFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 2
+	policy.InitialBackoff = time.Millisecond
+
+	client := NewClient(server.URL, WithLogger(logger), WithRetryPolicy(policy))
+	client.SetToken("secret-token")
+	client.Quote.Get("EURUSD")
+
+	out := buf.String()
+	if !strings.Contains(out, `"msg":"retrying request"`) || !strings.Contains(out, `"endpoint":"/GetQuote"`) {
+		t.Errorf("expected structured retry log, got %s", out)
+	}
+	if strings.Contains(out, "secret-token") {
+		t.Errorf("token leaked into logs: %s", out)
+	}
+}
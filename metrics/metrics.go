@@ -0,0 +1,263 @@
+// Package metrics เก็บ metrics ของ mt5client (REST และ WebSocket)
+// และแสดงผลในรูปแบบ Prometheus text format ผ่าน http.Handler
+//
+// ตัวอย่าง:
+//
+//	collector := metrics.New()
+//	client := mt5client.NewClient(url, mt5client.WithMiddleware(collector.Middleware()))
+//	ws := client.NewWebSocketClient()
+//	ws.SetObserver(collector)
+//	http.Handle("/metrics", collector.Handler())
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ditthkr/mt5client"
+)
+
+// DefaultBuckets bucket ของ histogram latency (วินาที)
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Collector เก็บ metrics ทั้งหมด ใช้ร่วมกันได้หลาย goroutine
+type Collector struct {
+	namespace string
+	buckets   []float64
+
+	mu            sync.Mutex
+	requests      map[requestKey]*histogram
+	wsMessages    map[string]uint64
+	wsParseErrors map[string]uint64
+	wsReconnects  map[string]uint64
+	wsUp          map[string]bool
+}
+
+// requestKey label ของ request histogram
+type requestKey struct {
+	endpoint string
+	status   string
+}
+
+// histogram histogram แบบ cumulative bucket
+type histogram struct {
+	counts []uint64 // จำนวนต่อ bucket (ไม่ cumulative)
+	count  uint64
+	sum    float64
+}
+
+// Option ตัวเลือกสำหรับ New
+type Option func(*Collector)
+
+// WithNamespace กำหนด prefix ของชื่อ metric (default: "mt5client")
+func WithNamespace(namespace string) Option {
+	return func(c *Collector) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets กำหนด bucket ของ latency histogram (วินาที, เรียงจากน้อยไปมาก)
+func WithBuckets(buckets []float64) Option {
+	return func(c *Collector) {
+		c.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(c.buckets)
+	}
+}
+
+// New สร้าง Collector ใหม่
+func New(opts ...Option) *Collector {
+	c := &Collector{
+		namespace:     "mt5client",
+		buckets:       DefaultBuckets,
+		requests:      make(map[requestKey]*histogram),
+		wsMessages:    make(map[string]uint64),
+		wsParseErrors: make(map[string]uint64),
+		wsReconnects:  make(map[string]uint64),
+		wsUp:          make(map[string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Middleware คืน middleware สำหรับวัด latency ของทุก REST request (รวมทุกครั้งที่ retry)
+func (c *Collector) Middleware() mt5client.Middleware {
+	return func(next mt5client.RoundTrip) mt5client.RoundTrip {
+		return func(ctx context.Context, req *mt5client.APIRequest) (*mt5client.APIResponse, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			c.ObserveRequest(req.Endpoint, statusLabel(resp, err), time.Since(start))
+			return resp, err
+		}
+	}
+}
+
+// statusLabel แปลงผลลัพธ์เป็น label status
+func statusLabel(resp *mt5client.APIResponse, err error) string {
+	if resp != nil && resp.StatusCode > 0 {
+		return strconv.Itoa(resp.StatusCode)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return "canceled"
+	}
+	if err != nil {
+		return "error"
+	}
+	return "unknown"
+}
+
+// ObserveRequest บันทึก latency ของ request หนึ่งครั้ง
+func (c *Collector) ObserveRequest(endpoint, status string, duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := requestKey{endpoint: endpoint, status: status}
+	h, ok := c.requests[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		c.requests[key] = h
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// OnMessage implements mt5client.WebSocketObserver
+func (c *Collector) OnMessage(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wsMessages[path]++
+}
+
+// OnParseError implements mt5client.WebSocketObserver
+func (c *Collector) OnParseError(path string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wsParseErrors[path]++
+}
+
+// OnReconnect implements mt5client.WebSocketObserver
+func (c *Collector) OnReconnect(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wsReconnects[path]++
+}
+
+// OnConnectionState implements mt5client.WebSocketObserver
+func (c *Collector) OnConnectionState(path string, up bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wsUp[path] = up
+}
+
+// Handler คืน http.Handler ที่แสดง metrics ในรูปแบบ Prometheus text format
+func (c *Collector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.WriteTo(w)
+	})
+}
+
+// WriteTo เขียน metrics ทั้งหมดในรูปแบบ Prometheus text format
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var b strings.Builder
+
+	name := c.namespace + "_request_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s Latency of MT5 REST requests by endpoint and status.\n", name)
+	fmt.Fprintf(&b, "# TYPE %s histogram\n", name)
+	keys := make([]requestKey, 0, len(c.requests))
+	for key := range c.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		return keys[i].status < keys[j].status
+	})
+	for _, key := range keys {
+		h := c.requests[key]
+		labels := fmt.Sprintf(`endpoint="%s",status="%s"`, escape(key.endpoint), escape(key.status))
+		var cumulative uint64
+		for i, bound := range c.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatFloat(bound), cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+		fmt.Fprintf(&b, "%s_sum{%s} %s\n", name, labels, formatFloat(h.sum))
+		fmt.Fprintf(&b, "%s_count{%s} %d\n", name, labels, h.count)
+	}
+
+	writeCounter(&b, c.namespace+"_ws_messages_received_total", "WebSocket messages received by stream path.", c.wsMessages)
+	writeCounter(&b, c.namespace+"_ws_parse_errors_total", "WebSocket messages that failed to parse by stream path.", c.wsParseErrors)
+	writeCounter(&b, c.namespace+"_ws_reconnects_total", "Successful WebSocket reconnects by stream path.", c.wsReconnects)
+
+	gauge := make(map[string]uint64, len(c.wsUp))
+	for path, up := range c.wsUp {
+		if up {
+			gauge[path] = 1
+		} else {
+			gauge[path] = 0
+		}
+	}
+	name = c.namespace + "_ws_connection_up"
+	fmt.Fprintf(&b, "# HELP %s Whether the WebSocket stream is connected (1) or not (0).\n", name)
+	fmt.Fprintf(&b, "# TYPE %s gauge\n", name)
+	writeSamples(&b, name, gauge)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// writeCounter เขียน counter ที่มี label path
+func writeCounter(b *strings.Builder, name, help string, values map[string]uint64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s counter\n", name)
+	writeSamples(b, name, values)
+}
+
+// writeSamples เขียนค่าตาม path เรียงตามชื่อ
+func writeSamples(b *strings.Builder, name string, values map[string]uint64) {
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fmt.Fprintf(b, "%s{path=\"%s\"} %d\n", name, escape(path), values[path])
+	}
+}
+
+// escape escape ค่า label ตาม Prometheus text format
+func escape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// formatFloat แปลง float เป็น string แบบสั้นที่สุด
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ditthkr/mt5client"
+)
+
+func TestCollectorRequestsAndWebSocket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/GetQuote" {
+			w.Write([]byte(`{"symbol":"EURUSD"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	collector := New()
+	client := mt5client.NewClient(server.URL, mt5client.WithMiddleware(collector.Middleware()))
+	client.SetToken("token")
+
+	client.Quote.Get("EURUSD")
+	client.Quote.Get("EURUSD")
+	client.Account.GetInfo()
+
+	collector.OnMessage("/OnQuote")
+	collector.OnParseError("/OnQuote", errors.New("bad json"))
+	collector.OnReconnect("/OnQuote")
+	collector.OnConnectionState("/OnQuote", true)
+	collector.OnConnectionState("/OnMail", false)
+
+	rec := httptest.NewRecorder()
+	collector.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	expected := []string{
+		`mt5client_request_duration_seconds_count{endpoint="/GetQuote",status="200"} 2`,
+		`mt5client_request_duration_seconds_count{endpoint="/Account",status="400"} 1`,
+		`mt5client_request_duration_seconds_bucket{endpoint="/GetQuote",status="200",le="+Inf"} 2`,
+		`mt5client_ws_messages_received_total{path="/OnQuote"} 1`,
+		`mt5client_ws_parse_errors_total{path="/OnQuote"} 1`,
+		`mt5client_ws_reconnects_total{path="/OnQuote"} 1`,
+		`mt5client_ws_connection_up{path="/OnQuote"} 1`,
+		`mt5client_ws_connection_up{path="/OnMail"} 0`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("missing %q in output:\n%s", line, body)
+		}
+	}
+}
+
+var _ mt5client.WebSocketObserver = (*Collector)(nil)
@@ -0,0 +1,42 @@
+package mt5client
+
+import "context"
+
+// APIRequest ข้อมูลคำขอที่ส่งผ่าน middleware
+type APIRequest struct {
+	Method   string            // HTTP method
+	Endpoint string            // endpoint เช่น /GetQuote
+	Params   map[string]string // query parameters (มี token/รหัสผ่าน ใช้ RedactParams ก่อน log)
+	Body     interface{}       // request body (ถ้ามี)
+	Result   interface{}       // ปลายทางสำหรับ decode response
+	Attempt  int               // ครั้งที่ส่ง (เริ่มที่ 1, มากกว่า 1 เมื่อ retry)
+}
+
+// APIResponse ผลลัพธ์ที่ได้จาก server
+type APIResponse struct {
+	StatusCode int         // HTTP status code
+	Body       []byte      // response body ดิบ
+	Result     interface{} // ผลลัพธ์ที่ decode แล้ว (ชี้ไปที่ APIRequest.Result)
+}
+
+// RoundTrip ส่งคำขอหนึ่งครั้งและคืนผลลัพธ์
+type RoundTrip func(ctx context.Context, req *APIRequest) (*APIResponse, error)
+
+// Middleware ครอบ RoundTrip เพื่อ log, trace, แก้ไข หรือตอบกลับแทน (short-circuit)
+type Middleware func(next RoundTrip) RoundTrip
+
+// WithMiddleware เพิ่ม middleware (ตัวแรกจะอยู่นอกสุด)
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// chainMiddlewares ประกอบ middleware รอบ RoundTrip ชั้นในสุด
+func chainMiddlewares(final RoundTrip, middlewares []Middleware) RoundTrip {
+	rt := final
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		rt = middlewares[i](rt)
+	}
+	return rt
+}
@@ -0,0 +1,84 @@
+package mt5client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMiddlewareOrderAndDecodedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"symbol":"EURUSD","bid":1.1,"ask":1.2}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	var seen *Quote
+	trace := func(name string) Middleware {
+		return func(next RoundTrip) RoundTrip {
+			return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+				order = append(order, name+":before")
+				resp, err := next(ctx, req)
+				order = append(order, name+":after")
+				if q, ok := resp.Result.(*Quote); ok {
+					seen = q
+				}
+				return resp, err
+			}
+		}
+	}
+
+	client := NewClient(server.URL, WithMiddleware(trace("outer"), trace("inner")))
+	client.SetToken("token")
+
+	if _, err := client.Quote.Get("EURUSD"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	want := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	if len(order) != len(want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, order)
+			break
+		}
+	}
+
+	if seen == nil || seen.Bid != 1.1 {
+		t.Errorf("middleware did not see decoded quote: %+v", seen)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	cache := func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+			if req.Endpoint == "/Version" {
+				return DecodeResponse(req, http.StatusOK, []byte("cached"))
+			}
+			return next(ctx, req)
+		}
+	}
+
+	client := NewClient(server.URL, WithMiddleware(cache))
+
+	version, err := client.Service.GetVersion()
+	if err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	if version != "cached" {
+		t.Errorf("expected cached, got %s", version)
+	}
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("expected no server calls, got %d", got)
+	}
+}
@@ -0,0 +1,1009 @@
+// Package mt5test จำลอง MT5 REST/WebSocket gateway แบบ in-process สำหรับ integration test
+//
+// ตัวอย่าง:
+//
+//	srv := mt5test.NewServer()
+//	defer srv.Close()
+//	srv.AddAccount(mt5client.Account{Login: 1001, Balance: 10000, Currency: "USD"}, "secret")
+//	srv.AddSymbol(mt5test.ForexSymbol("EURUSD", 5))
+//	srv.SetQuote("EURUSD", 1.1000, 1.1002)
+//
+//	client := mt5client.NewClient(srv.URL)
+//	client.Connection.Connect(mt5client.ConnectParams{User: 1001, Password: "secret"})
+//	client.Trading.Buy("EURUSD", 0.1, 0, 0)
+package mt5test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ditthkr/mt5client"
+	"github.com/gorilla/websocket"
+)
+
+// Server fake MT5 gateway (ใช้ร่วมกันได้หลาย goroutine)
+type Server struct {
+	*httptest.Server
+
+	// Now นาฬิกาที่ใช้ประทับเวลา order/quote (แทนที่ได้เพื่อให้ผลลัพธ์คงที่)
+	Now func() time.Time
+
+	mu           sync.Mutex
+	tokenHeader  string
+	accounts     map[int64]*account
+	sessions     map[string]int64 // token -> login
+	nextSession  int64
+	nextTicket   int64
+	symbols      map[string]mt5client.SymbolParams
+	quotes       map[string]mt5client.Quote
+	bars         map[string][]mt5client.Bar // key = symbol + "|" + timeframe
+	failures     map[string][]Failure
+	requestCount map[string]int
+	lastQuery    map[string]url.Values
+	hosts        map[string]host
+	downServers  map[string]bool
+	streams      map[string][]*wsConn // key = path เช่น /OnQuote
+}
+
+// account สถานะของบัญชีในเซิร์ฟเวอร์จำลอง
+type account struct {
+	info     mt5client.Account
+	password string
+	opened   []mt5client.Order
+	closed   []mt5client.Order
+}
+
+// host ผลของ /PingHost สำหรับ host หนึ่ง
+type host struct {
+	latency   time.Duration
+	reachable bool
+}
+
+// Failure คำตอบ error ที่จะถูกส่งแทนคำตอบปกติ (ใช้ทดสอบ error/retry)
+type Failure struct {
+	StatusCode int
+	Body       string
+	// Applied ทำตาม request จริงก่อนตอบ error (จำลองคำสั่งที่ server รับแล้วแต่ response หาย เช่น timeout)
+	Applied bool
+}
+
+// wsConn WebSocket connection ของ client หนึ่งตัว
+type wsConn struct {
+	mu    sync.Mutex
+	conn  *websocket.Conn
+	login int64
+}
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// NewServer สร้างและเริ่ม fake gateway
+func NewServer() *Server {
+	s := &Server{
+		Now:          time.Now,
+		accounts:     make(map[int64]*account),
+		sessions:     make(map[string]int64),
+		nextTicket:   1000,
+		symbols:      make(map[string]mt5client.SymbolParams),
+		quotes:       make(map[string]mt5client.Quote),
+		bars:         make(map[string][]mt5client.Bar),
+		failures:     make(map[string][]Failure),
+		requestCount: make(map[string]int),
+		lastQuery:    make(map[string]url.Values),
+		hosts:        make(map[string]host),
+		downServers:  make(map[string]bool),
+		streams:      make(map[string][]*wsConn),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close ปิด WebSocket ทั้งหมดและหยุดเซิร์ฟเวอร์
+func (s *Server) Close() {
+	s.mu.Lock()
+	for path, conns := range s.streams {
+		for _, c := range conns {
+			c.conn.Close()
+		}
+		delete(s.streams, path)
+	}
+	s.mu.Unlock()
+
+	s.Server.Close()
+}
+
+// AddAccount เพิ่มบัญชีที่ login ได้
+func (s *Server) AddAccount(info mt5client.Account, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if info.Equity == 0 {
+		info.Equity = info.Balance
+	}
+	if info.MarginFree == 0 {
+		info.MarginFree = info.Equity
+	}
+	s.accounts[info.Login] = &account{info: info, password: password}
+}
+
+// AddSymbol เพิ่มสัญลักษณ์
+func (s *Server) AddSymbol(params mt5client.SymbolParams) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.symbols[params.Symbol] = params
+}
+
+// ForexSymbol สร้าง SymbolParams แบบ forex ทั่วไป (contract 100,000, lot 0.01-100)
+// สกุลเงินอ่านจาก 6 ตัวแรกของชื่อ ถ้าชื่อสั้นกว่านั้น (เช่น "US30") จะเว้นว่างไว้
+func ForexSymbol(symbol string, digits int) mt5client.SymbolParams {
+	point := 1.0
+	for i := 0; i < digits; i++ {
+		point /= 10
+	}
+
+	var base, quote string
+	if len(symbol) >= 6 {
+		base, quote = symbol[:3], symbol[3:6]
+	}
+
+	return mt5client.SymbolParams{
+		Symbol: symbol,
+		SymbolInfo: mt5client.SymbolInfo{
+			Currency:       base,
+			ProfitCurrency: quote,
+			MarginCurrency: base,
+			Digits:         digits,
+			Points:         point,
+			TickSize:       point,
+			ContractSize:   100000,
+		},
+		SymbolGroup: mt5client.SymbolGroup{
+			TradeMode:  "Full",
+			FillPolicy: "FillOrKill",
+			Expiration: "All",
+			MinLots:    0.01,
+			MaxLots:    100,
+			LotsStep:   0.01,
+		},
+	}
+}
+
+// SetQuote ตั้งราคาและส่ง quote ไปยัง /OnQuote subscribers
+func (s *Server) SetQuote(symbol string, bid, ask float64) {
+	quote := mt5client.Quote{Symbol: symbol, Bid: bid, Ask: ask, Time: s.Now()}
+
+	s.mu.Lock()
+	s.quotes[symbol] = quote
+	s.mu.Unlock()
+
+	s.Push("/OnQuote", map[string]interface{}{"type": "Quote", "data": quote})
+}
+
+// SetBars กำหนดแท่งเทียนที่ /PriceHistory จะส่งกลับ
+func (s *Server) SetBars(symbol, timeframe string, bars []mt5client.Bar) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bars[symbol+"|"+timeframe] = bars
+}
+
+// FailNext ให้ endpoint ตอบ error ตามลำดับที่กำหนดก่อนกลับไปตอบปกติ
+func (s *Server) FailNext(endpoint string, failures ...Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[endpoint] = append(s.failures[endpoint], failures...)
+}
+
+// ExpireSessions ทำให้ token ทั้งหมดใช้ไม่ได้และตัด WebSocket stream ทั้งหมด (จำลอง session หมดอายุ)
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]int64)
+	for _, conns := range s.streams {
+		for _, c := range conns {
+			c.conn.Close()
+		}
+	}
+}
+
+// SetTokenHeader รับ session token จาก header นี้ด้วย (ใช้กับ client ที่ตั้ง WithTokenHeader)
+func (s *Server) SetTokenHeader(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokenHeader = name
+}
+
+// SetHost กำหนด latency และการเข้าถึงของ host สำหรับ /PingHost และ /PingHostMany
+// (host ที่ไม่ได้กำหนดจะเข้าถึงได้ทันที)
+func (s *Server) SetHost(name string, latency time.Duration, reachable bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hosts[name] = host{latency: latency, reachable: reachable}
+}
+
+// SetServerDown ให้การเชื่อมต่อไปยัง MT5 server นี้ล้มเหลวด้วย CONNECT_FAILED (จำลอง server ล่ม)
+func (s *Server) SetServerDown(server string, down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.downServers[server] = down
+}
+
+// RequestCount จำนวนครั้งที่ endpoint ถูกเรียก
+func (s *Server) RequestCount(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requestCount[endpoint]
+}
+
+// LastQuery query ของ request ล่าสุดที่ส่งไปยัง endpoint (nil ถ้ายังไม่เคยถูกเรียก)
+func (s *Server) LastQuery(endpoint string) url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastQuery[endpoint]
+}
+
+// OpenedOrders คำสั่งที่เปิดอยู่ของบัญชี
+func (s *Server) OpenedOrders(login int64) []mt5client.Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, ok := s.accounts[login]
+	if !ok {
+		return nil
+	}
+	return append([]mt5client.Order(nil), acc.opened...)
+}
+
+// AddClosedOrder เพิ่มคำสั่งในประวัติของบัญชี (เช่นคำสั่งที่โดน SL ไปแล้ว)
+func (s *Server) AddClosedOrder(login int64, order mt5client.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if acc, ok := s.accounts[login]; ok {
+		acc.closed = append(acc.closed, order)
+	}
+}
+
+// ClosedOrders คำสั่งที่ปิดแล้วของบัญชี
+func (s *Server) ClosedOrders(login int64) []mt5client.Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, ok := s.accounts[login]
+	if !ok {
+		return nil
+	}
+	return append([]mt5client.Order(nil), acc.closed...)
+}
+
+// Push ส่ง payload (แปลงเป็น JSON) ไปยังทุก connection ของ path เช่น "/OnQuote"
+func (s *Server) Push(path string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	conns := append([]*wsConn(nil), s.streams[path]...)
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		c.write(data)
+	}
+}
+
+// pushToLogin ส่ง payload ไปยัง connection ของบัญชีที่ระบุ
+func (s *Server) pushToLogin(path string, login int64, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	var conns []*wsConn
+	for _, c := range s.streams[path] {
+		if c.login == login {
+			conns = append(conns, c)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		c.write(data)
+	}
+}
+
+// write เขียนข้อความ (gorilla/websocket ไม่รองรับการเขียนพร้อมกัน)
+func (c *wsConn) write(data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// serveHTTP dispatch ตาม path
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := r.URL.Path
+
+	s.mu.Lock()
+	s.requestCount[endpoint]++
+	s.lastQuery[endpoint] = r.URL.Query()
+	var failure *Failure
+	if queue := s.failures[endpoint]; len(queue) > 0 {
+		failure = &queue[0]
+		s.failures[endpoint] = queue[1:]
+	}
+	s.mu.Unlock()
+
+	if failure != nil {
+		if handler, ok := restHandlers[endpoint]; ok && failure.Applied {
+			handler(s, httptest.NewRecorder(), r)
+		}
+		w.WriteHeader(failure.StatusCode)
+		w.Write([]byte(failure.Body))
+		return
+	}
+
+	if strings.HasPrefix(endpoint, "/On") {
+		s.serveStream(w, r)
+		return
+	}
+
+	handler, ok := restHandlers[endpoint]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "unknown endpoint "+endpoint)
+		return
+	}
+
+	handler(s, w, r)
+}
+
+// restHandlers endpoint ที่รองรับ
+var restHandlers = map[string]func(*Server, http.ResponseWriter, *http.Request){
+	"/Connect":             (*Server).handleConnect,
+	"/ConnectEx":           (*Server).handleConnect,
+	"/ConnectProxy":        (*Server).handleConnect,
+	"/Disconnect":          (*Server).handleDisconnect,
+	"/CheckConnect":        (*Server).handleCheckConnect,
+	"/Ping":                (*Server).handlePing,
+	"/Version":             (*Server).handleVersion,
+	"/PingHost":            (*Server).handlePingHost,
+	"/PingHostMany":        (*Server).handlePingHostMany,
+	"/Account":             (*Server).handleAccount,
+	"/AccountDetails":      (*Server).handleAccount,
+	"/OrderSend":           (*Server).handleOrderSend,
+	"/OrderModify":         (*Server).handleOrderModify,
+	"/OrderClose":          (*Server).handleOrderClose,
+	"/OpenedOrders":        (*Server).handleOpenedOrders,
+	"/OpenedOrder":         (*Server).handleOpenedOrder,
+	"/OpenedOrdersTickets": (*Server).handleOpenedOrdersTickets,
+	"/ClosedOrders":        (*Server).handleClosedOrders,
+	"/OrderHistory":        (*Server).handleOrderHistory,
+	"/GetQuote":            (*Server).handleGetQuote,
+	"/GetQuoteMany":        (*Server).handleGetQuoteMany,
+	"/SymbolList":          (*Server).handleSymbolList,
+	"/SymbolParams":        (*Server).handleSymbolParams,
+	"/SymbolParamsMany":    (*Server).handleSymbolParamsMany,
+	"/PriceHistory":        (*Server).handlePriceHistory,
+	"/Subscribe":           (*Server).handleSubscribe,
+	"/SubscribeMany":       (*Server).handleSubscribe,
+	"/UnSubscribe":         (*Server).handleSubscribe,
+	"/UnSubscribeMany":     (*Server).handleSubscribe,
+	"/IsQuoteSession":      (*Server).handleSession,
+	"/IsTradeSession":      (*Server).handleSession,
+}
+
+// writeJSON ตอบกลับเป็น JSON
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeText ตอบกลับเป็น text ธรรมดา (เช่น token, "OK")
+func writeText(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(text))
+}
+
+// writeError ตอบกลับ error ในรูปแบบ {"code","message"}
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"code": code, "message": message})
+}
+
+// token อ่าน session token จาก query "id" หรือ header ที่ตั้งด้วย SetTokenHeader
+// ต้องถือ s.mu อยู่
+func (s *Server) token(r *http.Request) string {
+	if token := r.URL.Query().Get("id"); token != "" || s.tokenHeader == "" {
+		return token
+	}
+	return r.Header.Get(s.tokenHeader)
+}
+
+// session ตรวจ token และคืนบัญชี (เขียน error แล้วคืน nil ถ้าไม่ผ่าน)
+// ต้องถือ s.mu อยู่
+func (s *Server) session(w http.ResponseWriter, r *http.Request) *account {
+	login, ok := s.sessions[s.token(r)]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "INVALID_TOKEN", "Invalid token")
+		return nil
+	}
+	return s.accounts[login]
+}
+
+// arrayParam อ่าน query แบบ name[0], name[1], ...
+func arrayParam(r *http.Request, name string) []string {
+	query := r.URL.Query()
+	var values []string
+	for i := 0; ; i++ {
+		key := fmt.Sprintf("%s[%d]", name, i)
+		if !query.Has(key) {
+			break
+		}
+		values = append(values, query.Get(key))
+	}
+	return values
+}
+
+// floatParam อ่าน query เป็น float64 (ไม่มี = 0)
+func floatParam(r *http.Request, name string) float64 {
+	f, _ := strconv.ParseFloat(r.URL.Query().Get(name), 64)
+	return f
+}
+
+// allowsPolicy ตรวจว่า value อยู่ในรายการ policy ของสัญลักษณ์ (คั่นด้วย "," หรือ "|" ค่าว่าง/"All" = อนุญาตทั้งหมด)
+func allowsPolicy(allowed, value string) bool {
+	fields := strings.FieldsFunc(allowed, func(r rune) bool { return r == ',' || r == '|' || r == ' ' })
+	if len(fields) == 0 {
+		return true
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field, "All") || strings.EqualFold(field, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// credential อ่านรหัสผ่านจาก JSON body (POST) หรือ query
+func credential(r *http.Request, name string) string {
+	if r.Method == http.MethodPost {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err == nil {
+			return body[name]
+		}
+	}
+	return r.URL.Query().Get(name)
+}
+
+func (s *Server) handleConnect(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	login, _ := strconv.ParseInt(query.Get("user"), 10, 64)
+	password := credential(r, "password")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, ok := s.accounts[login]
+	if !ok || acc.password != password {
+		writeError(w, http.StatusBadRequest, "INVALID_ACCOUNT", "Invalid account")
+		return
+	}
+	if server := query.Get("server"); server != "" && acc.info.Server != "" && server != acc.info.Server {
+		writeError(w, http.StatusBadRequest, "SERVER_NOT_FOUND", "Server not found")
+		return
+	}
+	if s.downServers[query.Get("server")] {
+		writeError(w, http.StatusBadRequest, "CONNECT_FAILED", "Cannot reach server")
+		return
+	}
+
+	s.nextSession++
+	token := fmt.Sprintf("session-%d-%d", login, s.nextSession)
+	s.sessions[token] = login
+
+	writeText(w, token)
+}
+
+func (s *Server) handleDisconnect(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	delete(s.sessions, s.token(r))
+	s.mu.Unlock()
+
+	writeText(w, "OK")
+}
+
+func (s *Server) handleCheckConnect(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session(w, r) == nil {
+		return
+	}
+	writeText(w, "OK")
+}
+
+func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
+	writeText(w, "pong")
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeText(w, "mt5test")
+}
+
+func (s *Server) handlePingHost(w http.ResponseWriter, r *http.Request) {
+	h := s.pingResult(r.URL.Query().Get("host"))
+	time.Sleep(h.latency)
+	writeJSON(w, h.reachable)
+}
+
+func (s *Server) handlePingHostMany(w http.ResponseWriter, r *http.Request) {
+	result := map[string]bool{}
+	for _, name := range arrayParam(r, "hosts") {
+		result[name] = s.pingResult(name).reachable
+	}
+	writeJSON(w, result)
+}
+
+// pingResult ค่าที่ตั้งไว้ด้วย SetHost (host ที่ไม่ได้กำหนดจะเข้าถึงได้ ยกเว้นชื่อว่าง)
+func (s *Server) pingResult(name string) host {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if h, ok := s.hosts[name]; ok {
+		return h
+	}
+	return host{reachable: name != ""}
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.session(w, r)
+	if acc == nil {
+		return
+	}
+	writeJSON(w, acc.info)
+}
+
+func (s *Server) handleOrderSend(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	symbol := query.Get("symbol")
+	operation, operationErr := mt5client.ParseOrderType(query.Get("operation"))
+	volume := floatParam(r, "volume")
+
+	s.mu.Lock()
+
+	acc := s.session(w, r)
+	if acc == nil {
+		s.mu.Unlock()
+		return
+	}
+
+	params, ok := s.symbols[symbol]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "INVALID_SYMBOL", "Symbol not found")
+		return
+	}
+	if expiration := query.Get("expirationType"); expiration != "" && !allowsPolicy(params.SymbolGroup.Expiration, expiration) {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "INVALID_EXPIRATION", "Invalid expiration "+expiration)
+		return
+	}
+	if fill := query.Get("fillPolicy"); fill != "" && !allowsPolicy(params.SymbolGroup.FillPolicy, fill) {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "INVALID_FILL", "Unsupported filling mode "+fill)
+		return
+	}
+	if volume <= 0 {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "INVALID_VOLUME", "Invalid volume")
+		return
+	}
+
+	quote, hasQuote := s.quotes[symbol]
+	price := floatParam(r, "price")
+	state := "Placed"
+
+	switch {
+	case operationErr != nil:
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "INVALID_OPERATION", "Invalid operation "+query.Get("operation"))
+		return
+	case !operation.IsPending():
+		if !hasQuote {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, "OFF_QUOTES", "No quotes")
+			return
+		}
+		price = quote.Bid
+		if operation.IsLong() {
+			price = quote.Ask
+		}
+		state = "Filled"
+	default:
+		if price <= 0 {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, "INVALID_PRICE", "Invalid price")
+			return
+		}
+		if (operation == mt5client.OrderBuyStopLimit || operation == mt5client.OrderSellStopLimit) && floatParam(r, "stoplimit") <= 0 {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, "INVALID_PRICE", "Invalid stop-limit price")
+			return
+		}
+	}
+
+	s.nextTicket++
+	now := s.Now()
+	order := mt5client.Order{
+		Ticket:           s.nextTicket,
+		OrderType:        operation,
+		Symbol:           symbol,
+		Lots:             volume,
+		OpenPrice:        price,
+		OpenTime:         now,
+		OpenTimestampUTC: now.UnixMilli(),
+		StopLoss:         floatParam(r, "stoploss"),
+		TakeProfit:       floatParam(r, "takeprofit"),
+		State:            state,
+		Comment:          query.Get("comment"),
+	}
+	order.MagicNumber, _ = strconv.ParseInt(query.Get("expertID"), 10, 64)
+	acc.opened = append(acc.opened, order)
+	login := acc.info.Login
+	s.mu.Unlock()
+
+	s.pushOrderUpdate(login, "MarketOpen", order)
+	writeJSON(w, order)
+}
+
+func (s *Server) handleOrderModify(w http.ResponseWriter, r *http.Request) {
+	ticket, _ := strconv.ParseInt(r.URL.Query().Get("ticket"), 10, 64)
+
+	s.mu.Lock()
+
+	acc := s.session(w, r)
+	if acc == nil {
+		s.mu.Unlock()
+		return
+	}
+
+	for i := range acc.opened {
+		order := &acc.opened[i]
+		if order.Ticket != ticket {
+			continue
+		}
+		if price := floatParam(r, "price"); price > 0 {
+			order.OpenPrice = price
+		}
+		if sl := floatParam(r, "stopLoss"); sl > 0 {
+			order.StopLoss = sl
+		}
+		if tp := floatParam(r, "takeProfit"); tp > 0 {
+			order.TakeProfit = tp
+		}
+		modified := *order
+		login := acc.info.Login
+		s.mu.Unlock()
+
+		s.pushOrderUpdate(login, "Modify", modified)
+		writeText(w, "OK")
+		return
+	}
+
+	s.mu.Unlock()
+	writeError(w, http.StatusBadRequest, "INVALID_TICKET", "Order not found")
+}
+
+func (s *Server) handleOrderClose(w http.ResponseWriter, r *http.Request) {
+	ticket, _ := strconv.ParseInt(r.URL.Query().Get("ticket"), 10, 64)
+	volume := floatParam(r, "volume")
+
+	s.mu.Lock()
+
+	acc := s.session(w, r)
+	if acc == nil {
+		s.mu.Unlock()
+		return
+	}
+
+	for i, order := range acc.opened {
+		if order.Ticket != ticket {
+			continue
+		}
+
+		now := s.Now()
+		closed := order
+		closed.CloseTime = now
+		closed.CloseTimestampUTC = now.UnixMilli()
+		closed.State = "Closed"
+		if quote, ok := s.quotes[order.Symbol]; ok {
+			closed.ClosePrice = quote.Ask
+			if order.OrderType.IsLong() {
+				closed.ClosePrice = quote.Bid
+			}
+		}
+
+		if volume > 0 && volume < order.Lots {
+			closed.Lots = volume
+			acc.opened[i].Lots = order.Lots - volume
+		} else {
+			acc.opened = append(acc.opened[:i], acc.opened[i+1:]...)
+		}
+		acc.closed = append(acc.closed, closed)
+		login := acc.info.Login
+		s.mu.Unlock()
+
+		s.pushOrderUpdate(login, "MarketClose", closed)
+		writeText(w, "OK")
+		return
+	}
+
+	s.mu.Unlock()
+	writeError(w, http.StatusBadRequest, "INVALID_TICKET", "Order not found")
+}
+
+// pushOrderUpdate ส่ง event ไปยัง /OnOrderUpdate ของบัญชี
+func (s *Server) pushOrderUpdate(login int64, updateType string, order mt5client.Order) {
+	var event mt5client.OrderUpdateEvent
+	event.Update.Type = updateType
+	event.Update.Order = order
+	s.pushToLogin("/OnOrderUpdate", login, map[string]interface{}{"type": "OrderUpdate", "data": event})
+}
+
+func (s *Server) handleOpenedOrders(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.session(w, r)
+	if acc == nil {
+		return
+	}
+	writeJSON(w, append([]mt5client.Order{}, acc.opened...))
+}
+
+func (s *Server) handleOpenedOrder(w http.ResponseWriter, r *http.Request) {
+	ticket, _ := strconv.ParseInt(r.URL.Query().Get("ticket"), 10, 64)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.session(w, r)
+	if acc == nil {
+		return
+	}
+	for _, order := range acc.opened {
+		if order.Ticket == ticket {
+			writeJSON(w, order)
+			return
+		}
+	}
+	writeError(w, http.StatusBadRequest, "INVALID_TICKET", "Order not found")
+}
+
+func (s *Server) handleOpenedOrdersTickets(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.session(w, r)
+	if acc == nil {
+		return
+	}
+	tickets := []int64{}
+	for _, order := range acc.opened {
+		tickets = append(tickets, order.Ticket)
+	}
+	writeJSON(w, tickets)
+}
+
+func (s *Server) handleClosedOrders(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.session(w, r)
+	if acc == nil {
+		return
+	}
+	writeJSON(w, append([]mt5client.Order{}, acc.closed...))
+}
+
+func (s *Server) handleOrderHistory(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.session(w, r)
+	if acc == nil {
+		return
+	}
+	orders := append(append([]mt5client.Order{}, acc.closed...), acc.opened...)
+	writeJSON(w, map[string]interface{}{"orders": orders})
+}
+
+func (s *Server) handleGetQuote(w http.ResponseWriter, r *http.Request) {
+	symbol := r.URL.Query().Get("symbol")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session(w, r) == nil {
+		return
+	}
+	quote, ok := s.quotes[symbol]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "INVALID_SYMBOL", "Symbol not found")
+		return
+	}
+	writeJSON(w, quote)
+}
+
+func (s *Server) handleGetQuoteMany(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session(w, r) == nil {
+		return
+	}
+	quotes := []mt5client.Quote{}
+	for _, symbol := range arrayParam(r, "symbols") {
+		if quote, ok := s.quotes[symbol]; ok {
+			quotes = append(quotes, quote)
+		}
+	}
+	writeJSON(w, quotes)
+}
+
+func (s *Server) handleSymbolList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session(w, r) == nil {
+		return
+	}
+	symbols := make([]string, 0, len(s.symbols))
+	for symbol := range s.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	writeJSON(w, symbols)
+}
+
+func (s *Server) handleSymbolParams(w http.ResponseWriter, r *http.Request) {
+	symbol := r.URL.Query().Get("symbol")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session(w, r) == nil {
+		return
+	}
+	params, ok := s.symbols[symbol]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "INVALID_SYMBOL", "Symbol not found")
+		return
+	}
+	writeJSON(w, params)
+}
+
+func (s *Server) handleSymbolParamsMany(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session(w, r) == nil {
+		return
+	}
+	result := []mt5client.SymbolParams{}
+	for _, symbol := range arrayParam(r, "symbols") {
+		if params, ok := s.symbols[symbol]; ok {
+			result = append(result, params)
+		}
+	}
+	writeJSON(w, result)
+}
+
+func (s *Server) handlePriceHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	count, _ := strconv.Atoi(query.Get("count"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session(w, r) == nil {
+		return
+	}
+	bars := s.bars[query.Get("symbol")+"|"+query.Get("timeframe")]
+	if count > 0 && count < len(bars) {
+		bars = bars[len(bars)-count:]
+	}
+	writeJSON(w, append([]mt5client.Bar{}, bars...))
+}
+
+func (s *Server) handleSubscribe(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session(w, r) == nil {
+		return
+	}
+	writeText(w, "OK")
+}
+
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	symbol := r.URL.Query().Get("symbol")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session(w, r) == nil {
+		return
+	}
+	_, ok := s.symbols[symbol]
+	writeJSON(w, ok)
+}
+
+// serveStream รับ WebSocket connection ของ /On* paths
+func (s *Server) serveStream(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	acc := s.session(w, r)
+	s.mu.Unlock()
+	if acc == nil {
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	c := &wsConn{conn: conn, login: acc.info.Login}
+	path := r.URL.Path
+
+	s.mu.Lock()
+	s.streams[path] = append(s.streams[path], c)
+	s.mu.Unlock()
+
+	c.write([]byte(`{"type":"Connected","data":"connected"}`))
+
+	// อ่านจนกว่า client จะปิด แล้วนำออกจากรายการ
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	conns := s.streams[path]
+	for i, existing := range conns {
+		if existing == c {
+			s.streams[path] = append(conns[:i], conns[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+	conn.Close()
+}
+
+// WaitForStream รอจนกว่าจะมี client เชื่อมต่อ path อย่างน้อย n ตัว (คืน false ถ้าหมดเวลา)
+func (s *Server) WaitForStream(path string, n int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		count := len(s.streams[path])
+		s.mu.Unlock()
+
+		if count >= n {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
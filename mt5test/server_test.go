@@ -0,0 +1,245 @@
+package mt5test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ditthkr/mt5client"
+)
+
+func newTestServer(t *testing.T) (*Server, *mt5client.Client) {
+	t.Helper()
+
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddAccount(mt5client.Account{Login: 1001, Balance: 10000, Currency: "USD", Server: "Demo-Server"}, "secret")
+	srv.AddSymbol(ForexSymbol("EURUSD", 5))
+	srv.SetQuote("EURUSD", 1.10000, 1.10020)
+
+	client := mt5client.NewClient(srv.URL, mt5client.WithLogger(nil))
+	if _, err := client.Connection.ConnectEx(1001, "secret", "Demo-Server"); err != nil {
+		t.Fatalf("ConnectEx failed: %v", err)
+	}
+
+	return srv, client
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	order, err := client.Trading.Buy("EURUSD", 0.1, 0, 0)
+	if err != nil {
+		t.Fatalf("Buy failed: %v", err)
+	}
+	if order.OpenPrice != 1.10020 {
+		t.Errorf("expected fill at ask 1.10020, got %f", order.OpenPrice)
+	}
+
+	opened, err := client.Order.GetOpened()
+	if err != nil {
+		t.Fatalf("GetOpened failed: %v", err)
+	}
+	if len(opened) != 1 || opened[0].Ticket != order.Ticket {
+		t.Fatalf("expected opened order %d, got %+v", order.Ticket, opened)
+	}
+
+	if err := client.Trading.Close(order.Ticket, 0); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if opened := srv.OpenedOrders(1001); len(opened) != 0 {
+		t.Errorf("expected no opened orders, got %d", len(opened))
+	}
+	closed, err := client.Order.GetClosed("", "")
+	if err != nil {
+		t.Fatalf("GetClosed failed: %v", err)
+	}
+	if len(closed) != 1 || closed[0].ClosePrice != 1.10000 {
+		t.Errorf("expected closed order at bid 1.10000, got %+v", closed)
+	}
+}
+
+func TestErrorsAndFailures(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	if _, err := client.Quote.Get("UNKNOWN"); !errors.Is(err, mt5client.ErrSymbolNotFound) {
+		t.Errorf("expected ErrSymbolNotFound, got %v", err)
+	}
+
+	srv.FailNext("/GetQuote", Failure{StatusCode: http.StatusServiceUnavailable})
+	if _, err := client.Quote.Get("EURUSD"); err != nil {
+		t.Errorf("expected retry to succeed, got %v", err)
+	}
+	if got := srv.RequestCount("/GetQuote"); got != 3 {
+		t.Errorf("expected 3 /GetQuote calls, got %d", got)
+	}
+
+	srv.ExpireSessions()
+	if _, err := client.Account.GetInfo(); !errors.Is(err, mt5client.ErrSessionExpired) {
+		t.Errorf("expected ErrSessionExpired, got %v", err)
+	}
+}
+
+func TestQuoteStream(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	quotes := make(chan *mt5client.Quote, 1)
+	ws := client.NewWebSocketClient()
+	ws.SetLogger(nil)
+	ws.SetHandlers(&mt5client.EventHandlers{
+		OnQuote: func(q *mt5client.Quote) { quotes <- q },
+	})
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer ws.Disconnect()
+
+	if err := ws.SubscribeQuote(); err != nil {
+		t.Fatalf("SubscribeQuote failed: %v", err)
+	}
+	if !srv.WaitForStream("/OnQuote", 1, time.Second) {
+		t.Fatal("client did not connect to /OnQuote")
+	}
+
+	srv.SetQuote("EURUSD", 1.2, 1.3)
+
+	select {
+	case q := <-quotes:
+		if q.Symbol != "EURUSD" || q.Bid != 1.2 {
+			t.Errorf("unexpected quote %+v", q)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for quote")
+	}
+}
+
+func TestConnectWithCredentialsInBody(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddAccount(mt5client.Account{Login: 1001}, "secret")
+
+	client := mt5client.NewClient(srv.URL, mt5client.WithLogger(nil), mt5client.WithCredentialsInBody())
+	if _, err := client.Connection.Connect(mt5client.ConnectParams{User: 1001, Password: "secret"}); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if _, err := client.Connection.Connect(mt5client.ConnectParams{User: 1001, Password: "wrong"}); err == nil {
+		t.Error("expected wrong password to be rejected")
+	}
+}
+
+func TestSessionReloginResubscribesStreams(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddAccount(mt5client.Account{Login: 1001}, "secret")
+	srv.AddSymbol(ForexSymbol("EURUSD", 5))
+
+	client := mt5client.NewClient(srv.URL, mt5client.WithLogger(nil))
+	session := mt5client.NewSession(client)
+	if _, err := session.ConnectEx(1001, "secret", ""); err != nil {
+		t.Fatalf("ConnectEx failed: %v", err)
+	}
+
+	quotes := make(chan *mt5client.Quote, 1)
+	ws := session.NewWebSocketClient()
+	ws.SetLogger(nil)
+	ws.SetHandlers(&mt5client.EventHandlers{
+		OnQuote: func(q *mt5client.Quote) { quotes <- q },
+	})
+	ws.Connect()
+	defer ws.Disconnect()
+	if err := ws.SubscribeQuote(); err != nil {
+		t.Fatalf("SubscribeQuote failed: %v", err)
+	}
+
+	srv.ExpireSessions()
+	if _, err := client.Account.GetInfo(); err != nil {
+		t.Fatalf("GetInfo after expiry failed: %v", err)
+	}
+	if n := srv.RequestCount("/ConnectEx"); n != 2 {
+		t.Fatalf("expected one re-login, got %d ConnectEx calls", n)
+	}
+	if !srv.WaitForStream("/OnQuote", 1, time.Second) {
+		t.Fatal("stream was not resubscribed")
+	}
+
+	// stream ใหม่อาจลงทะเบียนกับ server ช้ากว่า Dial เล็กน้อย จึงส่ง quote ซ้ำจนกว่าจะได้รับ
+	deadline := time.After(time.Second)
+	for {
+		srv.SetQuote("EURUSD", 1.2, 1.3)
+		select {
+		case <-quotes:
+			return
+		case <-deadline:
+			t.Fatal("timed out waiting for quote after re-login")
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+func TestPendingStopLimitOrder(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	order, err := client.Trading.SellStopLimit(mt5client.PendingOrder{
+		Symbol:         "EURUSD",
+		Volume:         0.2,
+		Price:          1.09000,
+		StopLimitPrice: 1.09100,
+		Expiration:     mt5client.ExpireToday(),
+	})
+	if err != nil {
+		t.Fatalf("SellStopLimit failed: %v", err)
+	}
+	if order.State != "Placed" || !order.OrderType.IsPending() || order.OrderType.IsLong() {
+		t.Errorf("unexpected pending order %+v", order)
+	}
+	if opened := srv.OpenedOrders(1001); len(opened) != 1 {
+		t.Errorf("expected 1 opened order, got %d", len(opened))
+	}
+}
+
+func TestMagicNumberFilter(t *testing.T) {
+	_, client := newTestServer(t)
+
+	for _, magic := range []int64{100, 200, 100} {
+		_, err := client.Trading.Send(mt5client.OrderRequest{
+			Symbol:      "EURUSD",
+			Type:        mt5client.OrderBuy,
+			Volume:      0.1,
+			Comment:     "bot",
+			MagicNumber: magic,
+		})
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+
+	orders, err := client.Order.GetOpenedFiltered(mt5client.ByMagic(100))
+	if err != nil {
+		t.Fatalf("GetOpenedFiltered failed: %v", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("expected 2 orders with magic 100, got %d", len(orders))
+	}
+	for _, order := range orders {
+		if order.MagicNumber != 100 {
+			t.Errorf("unexpected magic number %d", order.MagicNumber)
+		}
+	}
+}
+
+func TestForexSymbolShortName(t *testing.T) {
+	for _, symbol := range []string{"US30", "XAU", ""} {
+		params := ForexSymbol(symbol, 2)
+		if params.Symbol != symbol || params.SymbolInfo.Currency != "" {
+			t.Errorf("unexpected params for %q: %+v", symbol, params.SymbolInfo)
+		}
+	}
+
+	params := ForexSymbol("EURUSD.m", 5)
+	if params.SymbolInfo.Currency != "EUR" || params.SymbolInfo.ProfitCurrency != "USD" {
+		t.Errorf("unexpected currencies %+v", params.SymbolInfo)
+	}
+}
@@ -0,0 +1,142 @@
+package mt5client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option ตัวเลือกสำหรับ NewClient
+type Option func(*Client)
+
+// WithHTTPClient ใช้ http.Client ของผู้ใช้เอง (เช่น transport ที่มี mTLS, proxy, custom DNS)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithTimeout กำหนด timeout รวมของทุก request (default: 30 วินาที)
+// ใช้กับสำเนาของ http.Client จึงไม่แก้ client ที่ส่งมาใน WithHTTPClient
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = &timeout
+	}
+}
+
+// WithEndpointTimeout กำหนด timeout เฉพาะ endpoint เช่น WithEndpointTimeout("/PriceHistory", 2*time.Minute)
+// ยาวกว่า timeout รวมได้ (ดู applyHTTPOptions) และมีผลก่อน WithGroupTimeout
+func WithEndpointTimeout(endpoint string, timeout time.Duration) Option {
+	return func(c *Client) {
+		c.endpointTimeouts[endpoint] = timeout
+	}
+}
+
+// WithGroupTimeout กำหนด timeout ของทุก endpoint ใน group เช่น WithGroupTimeout(EndpointGroupTrading, 10*time.Second)
+func WithGroupTimeout(group EndpointGroup, timeout time.Duration) Option {
+	return func(c *Client) {
+		c.groupTimeouts[group] = timeout
+	}
+}
+
+// WithHeader เพิ่ม header ที่จะส่งไปกับทุก request
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithUserAgent กำหนด User-Agent
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.headers.Set("User-Agent", userAgent)
+	}
+}
+
+// WithBasePath กำหนด path ที่จะต่อท้าย baseURL ก่อน endpoint เช่น "/mt5"
+func WithBasePath(basePath string) Option {
+	return func(c *Client) {
+		c.basePath = "/" + strings.Trim(basePath, "/")
+		if c.basePath == "/" {
+			c.basePath = ""
+		}
+	}
+}
+
+// WithTLSConfig กำหนด TLS config สำหรับทั้ง REST และ WebSocket
+// ใช้กับสำเนาของ transport จึงไม่แก้ client ที่ส่งมาใน WithHTTPClient (transport ที่ไม่ใช่ *http.Transport จะไม่ถูกแตะต้อง)
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = config
+	}
+}
+
+// applyHTTPOptions ใช้ WithTimeout และ WithTLSConfig กับสำเนาของ http.Client หลัง Option ทั้งหมดทำงานแล้ว
+// (ผลจึงไม่ขึ้นกับลำดับ Option และไม่แก้ http.Client ที่ใช้ร่วมกับที่อื่น เช่น http.DefaultClient)
+//
+// ถ้ามี WithEndpointTimeout/WithGroupTimeout จะย้าย timeout รวมจาก http.Client.Timeout ไปเป็น context deadline
+// เพื่อไม่ให้ timeout เฉพาะ endpoint ที่ยาวกว่าถูกตัดที่ timeout รวม
+func (r *Client) applyHTTPOptions() {
+	perEndpoint := len(r.endpointTimeouts) > 0 || len(r.groupTimeouts) > 0
+	if r.timeout == nil && r.tlsConfig == nil && !perEndpoint {
+		return
+	}
+
+	httpClient := *r.httpClient
+	if r.timeout != nil {
+		httpClient.Timeout = *r.timeout
+	}
+	if perEndpoint {
+		r.requestTimeout = httpClient.Timeout
+		httpClient.Timeout = 0
+	}
+	if r.tlsConfig != nil {
+		switch t := httpClient.Transport.(type) {
+		case nil:
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = r.tlsConfig
+			httpClient.Transport = transport
+		case *http.Transport:
+			transport := t.Clone()
+			transport.TLSClientConfig = r.tlsConfig
+			httpClient.Transport = transport
+		default:
+			// transport ของผู้ใช้เอง ไม่แตะต้อง
+		}
+	}
+	r.httpClient = &httpClient
+}
+
+// WithTokenHeader ส่ง session token ผ่าน header ที่กำหนด (เช่น "Authorization") แทน query "id"
+// ใช้ได้เฉพาะ gateway ที่รองรับการอ่าน token จาก header
+func WithTokenHeader(name string) Option {
+	return func(c *Client) {
+		c.tokenHeader = name
+	}
+}
+
+// WithCredentialsInBody ส่งรหัสผ่านของ Connect, ConnectEx, ConnectProxy และ ChangePassword
+// ผ่าน JSON body (POST) แทน query string ใช้ได้เฉพาะ gateway ที่รับ POST สำหรับ endpoint เหล่านี้
+func WithCredentialsInBody() Option {
+	return func(c *Client) {
+		c.credentialsBody = true
+	}
+}
+
+// WithOrderValidation ตรวจสอบและปรับทุกคำสั่งที่ส่งผ่าน TradingService ตาม SymbolParams ก่อนส่ง /OrderSend
+// (volume ตาม LotsStep, ราคาตาม TickSize/Digits, ระยะ SL/TP และ TradeMode) ดู OrderValidator
+func WithOrderValidation() Option {
+	return func(c *Client) {
+		c.orderValidator = c.NewOrderValidator()
+	}
+}
+
+// WithIdempotentOrders ส่งทุกคำสั่งผ่าน TradingService.Send แบบ idempotent (ดู SendIdempotent)
+func WithIdempotentOrders() Option {
+	return func(c *Client) {
+		c.idempotentOrders = true
+	}
+}
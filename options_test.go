@@ -0,0 +1,133 @@
+package mt5client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientOptions(t *testing.T) {
+	var gotPath, gotUA, gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotUA = r.Header.Get("User-Agent")
+		gotHeader = r.Header.Get("X-Api-Key")
+		w.Write([]byte(`"5.0"`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/",
+		WithBasePath("/mt5/"),
+		WithUserAgent("bot/1.0"),
+		WithHeader("X-Api-Key", "secret"),
+	)
+
+	if _, err := client.Service.GetVersion(); err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+
+	if gotPath != "/mt5/Version" {
+		t.Errorf("expected path /mt5/Version, got %s", gotPath)
+	}
+	if gotUA != "bot/1.0" {
+		t.Errorf("expected user agent bot/1.0, got %s", gotUA)
+	}
+	if gotHeader != "secret" {
+		t.Errorf("expected X-Api-Key secret, got %s", gotHeader)
+	}
+
+	wsURL, err := client.Subscription.GetWebSocketURL()
+	if err != nil {
+		t.Fatalf("GetWebSocketURL failed: %v", err)
+	}
+	if wsURL != server.URL+"/mt5" {
+		t.Errorf("expected WebSocket URL %s/mt5, got %s", server.URL, wsURL)
+	}
+}
+
+func TestWithEndpointTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL, WithEndpointTimeout("/Ping", 50*time.Millisecond))
+
+	_, err := client.Service.Ping()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestEndpointTimeoutLongerThanClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("pong"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL,
+		WithLogger(nil),
+		WithRetryPolicy(NoRetryPolicy()),
+		WithTimeout(50*time.Millisecond),
+		WithEndpointTimeout("/Ping", time.Second),
+		WithGroupTimeout(EndpointGroupTrading, 2*time.Second),
+	)
+
+	if _, err := client.Service.Ping(); err != nil {
+		t.Fatalf("expected /Ping to use its own timeout, got %v", err)
+	}
+	if _, err := client.Service.GetVersion(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected client timeout for other endpoints, got %v", err)
+	}
+
+	for endpoint, want := range map[string]time.Duration{
+		"/Ping":      time.Second,
+		"/OrderSend": 2 * time.Second,
+		"/Account":   50 * time.Millisecond,
+	} {
+		if got := client.endpointTimeout(endpoint); got != want {
+			t.Errorf("%s: expected timeout %v, got %v", endpoint, want, got)
+		}
+	}
+}
+
+func TestHTTPOptionsDoNotModifySharedClient(t *testing.T) {
+	shared := &http.Client{Timeout: time.Minute}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	// ลำดับของ Option ต้องไม่มีผล
+	for _, opts := range [][]Option{
+		{WithHTTPClient(shared), WithTimeout(time.Second), WithTLSConfig(tlsConfig)},
+		{WithTLSConfig(tlsConfig), WithTimeout(time.Second), WithHTTPClient(shared)},
+	} {
+		client := NewClient("http://localhost", opts...)
+
+		if client.httpClient == shared {
+			t.Fatal("expected a copy of the shared http.Client")
+		}
+		if client.httpClient.Timeout != time.Second {
+			t.Errorf("expected timeout 1s, got %v", client.httpClient.Timeout)
+		}
+		transport, ok := client.httpClient.Transport.(*http.Transport)
+		if !ok || transport.TLSClientConfig != tlsConfig {
+			t.Error("expected TLS config on REST transport")
+		}
+	}
+
+	if shared.Timeout != time.Minute || shared.Transport != nil {
+		t.Errorf("shared client was modified: timeout %v, transport %v", shared.Timeout, shared.Transport)
+	}
+	if http.DefaultClient.Timeout != 0 {
+		t.Error("http.DefaultClient was modified")
+	}
+}
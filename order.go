@@ -1,6 +1,9 @@
 package mt5client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // OrderService จัดการคำสั่งซื้อขาย
 type OrderService struct {
@@ -9,16 +12,25 @@ type OrderService struct {
 
 // GetOpened ดึงคำสั่งที่เปิดอยู่ทั้งหมด
 func (r *OrderService) GetOpened() ([]Order, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetOpenedCtx(context.Background())
+}
+
+// GetOpenedCtx ดึงคำสั่งที่เปิดอยู่ทั้งหมด (รองรับ context)
+func (r *OrderService) GetOpenedCtx(ctx context.Context) ([]Order, error) {
+	ctx, span := r.client.startSpan(ctx, "OrderService.GetOpened")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var orders []Order
-	err := r.client.get("/OpenedOrders", queryParams, &orders)
+	err := r.client.get(ctx, "/OpenedOrders", queryParams, &orders)
 	if err != nil {
 		return nil, err
 	}
@@ -28,17 +40,26 @@ func (r *OrderService) GetOpened() ([]Order, error) {
 
 // GetOpenedByTicket ดึงคำสั่งที่เปิดอยู่ตาม ticket
 func (r *OrderService) GetOpenedByTicket(ticket int64) (*Order, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetOpenedByTicketCtx(context.Background(), ticket)
+}
+
+// GetOpenedByTicketCtx ดึงคำสั่งที่เปิดอยู่ตาม ticket (รองรับ context)
+func (r *OrderService) GetOpenedByTicketCtx(ctx context.Context, ticket int64) (*Order, error) {
+	ctx, span := r.client.startSpan(ctx, "OrderService.GetOpenedByTicket", Attr("ticket", ticket))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"ticket": fmt.Sprintf("%d", ticket),
 	}
 
 	var order Order
-	err := r.client.get("/OpenedOrder", queryParams, &order)
+	err := r.client.get(ctx, "/OpenedOrder", queryParams, &order)
 	if err != nil {
 		return nil, err
 	}
@@ -48,16 +69,25 @@ func (r *OrderService) GetOpenedByTicket(ticket int64) (*Order, error) {
 
 // GetOpenedTickets ดึง tickets ของคำสั่งที่เปิดอยู่
 func (r *OrderService) GetOpenedTickets() ([]int64, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetOpenedTicketsCtx(context.Background())
+}
+
+// GetOpenedTicketsCtx ดึง tickets ของคำสั่งที่เปิดอยู่ (รองรับ context)
+func (r *OrderService) GetOpenedTicketsCtx(ctx context.Context) ([]int64, error) {
+	ctx, span := r.client.startSpan(ctx, "OrderService.GetOpenedTickets")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var tickets []int64
-	err := r.client.get("/OpenedOrdersTickets", queryParams, &tickets)
+	err := r.client.get(ctx, "/OpenedOrdersTickets", queryParams, &tickets)
 	if err != nil {
 		return nil, err
 	}
@@ -67,18 +97,27 @@ func (r *OrderService) GetOpenedTickets() ([]int64, error) {
 
 // GetClosed ดึงคำสั่งที่ปิดแล้ว
 func (r *OrderService) GetClosed(from, to string) ([]Order, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetClosedCtx(context.Background(), from, to)
+}
+
+// GetClosedCtx ดึงคำสั่งที่ปิดแล้ว (รองรับ context)
+func (r *OrderService) GetClosedCtx(ctx context.Context, from, to string) ([]Order, error) {
+	ctx, span := r.client.startSpan(ctx, "OrderService.GetClosed", Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":   r.client.token,
+		"id":   token,
 		"from": from,
 		"to":   to,
 	}
 
 	var orders []Order
-	err := r.client.get("/ClosedOrders", queryParams, &orders)
+	err := r.client.get(ctx, "/ClosedOrders", queryParams, &orders)
 	if err != nil {
 		return nil, err
 	}
@@ -88,21 +127,58 @@ func (r *OrderService) GetClosed(from, to string) ([]Order, error) {
 
 // GetPendingHistory ดึงประวัติคำสั่ง pending
 func (r *OrderService) GetPendingHistory(from, to string) ([]Order, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetPendingHistoryCtx(context.Background(), from, to)
+}
+
+// GetPendingHistoryCtx ดึงประวัติคำสั่ง pending (รองรับ context)
+func (r *OrderService) GetPendingHistoryCtx(ctx context.Context, from, to string) ([]Order, error) {
+	ctx, span := r.client.startSpan(ctx, "OrderService.GetPendingHistory", Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":   r.client.token,
+		"id":   token,
 		"from": from,
 		"to":   to,
 	}
 
 	var orders []Order
-	err := r.client.get("/PendingOrderHistory", queryParams, &orders)
+	err := r.client.get(ctx, "/PendingOrderHistory", queryParams, &orders)
 	if err != nil {
 		return nil, err
 	}
 
 	return orders, nil
 }
+
+// GetOpenedFiltered ดึงคำสั่งที่เปิดอยู่เฉพาะที่ตรงกับ filter (เช่น ByMagic)
+func (r *OrderService) GetOpenedFiltered(filter OrderFilter) ([]Order, error) {
+	return r.GetOpenedFilteredCtx(context.Background(), filter)
+}
+
+// GetOpenedFilteredCtx ดึงคำสั่งที่เปิดอยู่เฉพาะที่ตรงกับ filter (รองรับ context)
+func (r *OrderService) GetOpenedFilteredCtx(ctx context.Context, filter OrderFilter) ([]Order, error) {
+	orders, err := r.GetOpenedCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return FilterOrders(orders, filter), nil
+}
+
+// GetClosedFiltered ดึงคำสั่งที่ปิดแล้วเฉพาะที่ตรงกับ filter
+func (r *OrderService) GetClosedFiltered(from, to string, filter OrderFilter) ([]Order, error) {
+	return r.GetClosedFilteredCtx(context.Background(), from, to, filter)
+}
+
+// GetClosedFilteredCtx ดึงคำสั่งที่ปิดแล้วเฉพาะที่ตรงกับ filter (รองรับ context)
+func (r *OrderService) GetClosedFilteredCtx(ctx context.Context, from, to string, filter OrderFilter) ([]Order, error) {
+	orders, err := r.GetClosedCtx(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return FilterOrders(orders, filter), nil
+}
@@ -0,0 +1,88 @@
+package mt5client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderType ชนิดของคำสั่ง (ค่าเดียวกับที่ server ใช้ใน "operation" และ "orderType")
+// ค่าที่ server ส่งมาแต่ไม่รู้จัก (เช่น "Balance" ในประวัติ) จะถูกเก็บไว้ตามเดิม ใช้ Valid ตรวจสอบ
+type OrderType string
+
+const (
+	OrderBuy           OrderType = "Buy"
+	OrderSell          OrderType = "Sell"
+	OrderBuyLimit      OrderType = "BuyLimit"
+	OrderSellLimit     OrderType = "SellLimit"
+	OrderBuyStop       OrderType = "BuyStop"
+	OrderSellStop      OrderType = "SellStop"
+	OrderBuyStopLimit  OrderType = "BuyStopLimit"
+	OrderSellStopLimit OrderType = "SellStopLimit"
+)
+
+// orderTypes ชนิดคำสั่งที่ส่งไปยัง /OrderSend ได้ (key เป็นตัวพิมพ์เล็กไม่มีตัวคั่น)
+var orderTypes = map[string]OrderType{
+	"buy":           OrderBuy,
+	"sell":          OrderSell,
+	"buylimit":      OrderBuyLimit,
+	"selllimit":     OrderSellLimit,
+	"buystop":       OrderBuyStop,
+	"sellstop":      OrderSellStop,
+	"buystoplimit":  OrderBuyStopLimit,
+	"sellstoplimit": OrderSellStopLimit,
+}
+
+// normalizeOrderType ตัดตัวคั่นและแปลงเป็นตัวพิมพ์เล็ก เช่น "Buy_Limit", "BUY LIMIT" → "buylimit"
+func normalizeOrderType(s string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// ParseOrderType แปลงชื่อชนิดคำสั่ง (ไม่สนตัวพิมพ์และตัวคั่น เช่น "buy_limit", "SELL STOP")
+func ParseOrderType(s string) (OrderType, error) {
+	if t, ok := orderTypes[normalizeOrderType(s)]; ok {
+		return t, nil
+	}
+	return "", fmt.Errorf("%w: unknown order type %q", ErrInvalidArgument, s)
+}
+
+// Valid ตรวจสอบว่าเป็นชนิดคำสั่งที่รองรับ
+func (t OrderType) Valid() bool {
+	parsed, ok := orderTypes[normalizeOrderType(string(t))]
+	return ok && parsed == t
+}
+
+// IsPending เป็นคำสั่งรอ (Limit, Stop, StopLimit)
+func (t OrderType) IsPending() bool {
+	return t.Valid() && t != OrderBuy && t != OrderSell
+}
+
+// IsLong เป็นฝั่งซื้อ (Buy และ pending ฝั่ง Buy)
+func (t OrderType) IsLong() bool {
+	return t.Valid() && strings.HasPrefix(string(t), "Buy")
+}
+
+// IsShort เป็นฝั่งขาย (Sell และ pending ฝั่ง Sell)
+func (t OrderType) IsShort() bool {
+	return t.Valid() && strings.HasPrefix(string(t), "Sell")
+}
+
+// String ชื่อชนิดคำสั่ง
+func (t OrderType) String() string {
+	return string(t)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (t OrderType) MarshalText() ([]byte, error) {
+	return []byte(t), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+// แปลงรูปแบบอื่นของชื่อที่รู้จักให้เป็นค่ามาตรฐาน ค่าที่ไม่รู้จักเก็บไว้ตามเดิม
+func (t *OrderType) UnmarshalText(text []byte) error {
+	if parsed, err := ParseOrderType(string(text)); err == nil {
+		*t = parsed
+		return nil
+	}
+	*t = OrderType(text)
+	return nil
+}
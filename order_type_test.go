@@ -0,0 +1,88 @@
+package mt5client
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseOrderType(t *testing.T) {
+	tests := map[string]OrderType{
+		"Buy":             OrderBuy,
+		"sell":            OrderSell,
+		"buy_limit":       OrderBuyLimit,
+		"SELL LIMIT":      OrderSellLimit,
+		"BuyStop":         OrderBuyStop,
+		"sell-stop":       OrderSellStop,
+		"BuyStopLimit":    OrderBuyStopLimit,
+		"sell_stop_limit": OrderSellStopLimit,
+	}
+	for input, want := range tests {
+		got, err := ParseOrderType(input)
+		if err != nil || got != want {
+			t.Errorf("ParseOrderType(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := ParseOrderType("BuyMarket"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestOrderTypePredicates(t *testing.T) {
+	tests := []struct {
+		typ     OrderType
+		pending bool
+		long    bool
+	}{
+		{OrderBuy, false, true},
+		{OrderSell, false, false},
+		{OrderBuyLimit, true, true},
+		{OrderSellStop, true, false},
+		{OrderSellStopLimit, true, false},
+	}
+	for _, tt := range tests {
+		if tt.typ.IsPending() != tt.pending || tt.typ.IsLong() != tt.long || tt.typ.IsShort() == tt.long {
+			t.Errorf("%s: unexpected predicates pending=%v long=%v", tt.typ, tt.typ.IsPending(), tt.typ.IsLong())
+		}
+	}
+
+	if OrderType("Balance").Valid() || OrderType("Balance").IsLong() {
+		t.Error("expected unknown type to be invalid")
+	}
+}
+
+func TestOrderTypeJSON(t *testing.T) {
+	var order Order
+	if err := json.Unmarshal([]byte(`{"ticket":1,"orderType":"buy_limit"}`), &order); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if order.OrderType != OrderBuyLimit {
+		t.Errorf("expected BuyLimit, got %q", order.OrderType)
+	}
+
+	// ค่าที่ไม่รู้จักจาก server ต้องไม่ทำให้ parse ทั้ง response ล้มเหลว
+	if err := json.Unmarshal([]byte(`{"ticket":2,"orderType":"Balance"}`), &order); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if order.OrderType != "Balance" {
+		t.Errorf("expected raw Balance, got %q", order.OrderType)
+	}
+
+	data, err := json.Marshal(OrderRequest{Symbol: "EURUSD", Type: OrderSellStop})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"symbol":"EURUSD","type":"SellStop","volume":0}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+}
+
+func TestSendRejectsUnknownOrderType(t *testing.T) {
+	client := NewClient("http://localhost:1", WithLogger(nil))
+	client.SetToken("token")
+
+	if _, err := client.Trading.Send(OrderRequest{Symbol: "EURUSD", Type: "buy_limit", Volume: 0.1}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument, got %v", err)
+	}
+}
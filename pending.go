@@ -0,0 +1,247 @@
+package mt5client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExpirationMode วิธีหมดอายุของ pending order
+type ExpirationMode string
+
+const (
+	ExpirationGTC       ExpirationMode = "GTC"       // อยู่จนกว่าจะยกเลิก
+	ExpirationToday     ExpirationMode = "Today"     // หมดอายุสิ้นวันเทรด
+	ExpirationSpecified ExpirationMode = "Specified" // หมดอายุตามเวลาที่กำหนด
+)
+
+// Expiration การหมดอายุของ pending order
+type Expiration struct {
+	Mode ExpirationMode `json:"mode"`
+	// Time ใช้กับ ExpirationSpecified เท่านั้น ส่งเป็นเวลาตามนาฬิกาของ server (ไม่มี timezone)
+	// โดยใช้ค่าวัน/เวลาตามที่เห็นใน Time ตรงๆ ไม่แปลง location ให้ (ดู ExpireAtIn)
+	Time time.Time `json:"time,omitempty"`
+}
+
+// GoodTillCancelled pending order ที่อยู่จนกว่าจะยกเลิก
+func GoodTillCancelled() *Expiration {
+	return &Expiration{Mode: ExpirationGTC}
+}
+
+// ExpireToday pending order ที่หมดอายุสิ้นวันเทรด
+func ExpireToday() *Expiration {
+	return &Expiration{Mode: ExpirationToday}
+}
+
+// ExpireAt pending order ที่หมดอายุตามเวลาที่กำหนด
+// t ต้องเป็นเวลาของ server อยู่แล้ว (location ของ t ไม่ถูกนำมาคำนวณ)
+func ExpireAt(t time.Time) *Expiration {
+	return &Expiration{Mode: ExpirationSpecified, Time: t}
+}
+
+// ExpireAtIn pending order ที่หมดอายุตามเวลา t โดยแปลงเป็นเวลาของ server ที่อยู่ใน location server ก่อน
+// เช่น ExpireAtIn(time.Now().Add(time.Hour), serverLocation)
+func ExpireAtIn(t time.Time, server *time.Location) *Expiration {
+	return ExpireAt(t.In(server))
+}
+
+// FillPolicy นโยบายการ fill คำสั่ง
+type FillPolicy string
+
+const (
+	FillOrKill            FillPolicy = "FillOrKill"        // fill ทั้งหมดหรือยกเลิก
+	FillImmediateOrCancel FillPolicy = "ImmediateOrCancel" // fill เท่าที่ได้ ที่เหลือยกเลิก
+	FillReturn            FillPolicy = "Return"            // fill เท่าที่ได้ ที่เหลือคงไว้
+)
+
+// PendingOrder พารามิเตอร์ของ BuyLimit, SellLimit, BuyStop, SellStop, BuyStopLimit และ SellStopLimit
+type PendingOrder struct {
+	Symbol         string
+	Volume         float64
+	Price          float64 // ราคาที่วาง (สำหรับ StopLimit คือราคา trigger)
+	StopLimitPrice float64 // ราคา limit ที่จะวางเมื่อราคาแตะ Price (เฉพาะ StopLimit)
+	StopLoss       float64
+	TakeProfit     float64
+	Expiration     *Expiration // nil = ค่า default ของ server
+	Deviation      int         // slippage สูงสุด (points)
+	FillPolicy     FillPolicy  // ว่าง = ค่า default ของ server
+	Comment        string
+	MagicNumber    int64
+}
+
+// BuyLimit วางคำสั่งซื้อที่ราคาต่ำกว่าปัจจุบัน
+func (r *TradingService) BuyLimit(order PendingOrder) (*Order, error) {
+	return r.BuyLimitCtx(context.Background(), order)
+}
+
+// BuyLimitCtx วางคำสั่งซื้อที่ราคาต่ำกว่าปัจจุบัน (รองรับ context)
+func (r *TradingService) BuyLimitCtx(ctx context.Context, order PendingOrder) (*Order, error) {
+	return r.sendPending(ctx, "TradingService.BuyLimit", OrderBuyLimit, order)
+}
+
+// SellLimit วางคำสั่งขายที่ราคาสูงกว่าปัจจุบัน
+func (r *TradingService) SellLimit(order PendingOrder) (*Order, error) {
+	return r.SellLimitCtx(context.Background(), order)
+}
+
+// SellLimitCtx วางคำสั่งขายที่ราคาสูงกว่าปัจจุบัน (รองรับ context)
+func (r *TradingService) SellLimitCtx(ctx context.Context, order PendingOrder) (*Order, error) {
+	return r.sendPending(ctx, "TradingService.SellLimit", OrderSellLimit, order)
+}
+
+// BuyStop วางคำสั่งซื้อเมื่อราคาขึ้นถึงระดับที่กำหนด
+func (r *TradingService) BuyStop(order PendingOrder) (*Order, error) {
+	return r.BuyStopCtx(context.Background(), order)
+}
+
+// BuyStopCtx วางคำสั่งซื้อเมื่อราคาขึ้นถึงระดับที่กำหนด (รองรับ context)
+func (r *TradingService) BuyStopCtx(ctx context.Context, order PendingOrder) (*Order, error) {
+	return r.sendPending(ctx, "TradingService.BuyStop", OrderBuyStop, order)
+}
+
+// SellStop วางคำสั่งขายเมื่อราคาลงถึงระดับที่กำหนด
+func (r *TradingService) SellStop(order PendingOrder) (*Order, error) {
+	return r.SellStopCtx(context.Background(), order)
+}
+
+// SellStopCtx วางคำสั่งขายเมื่อราคาลงถึงระดับที่กำหนด (รองรับ context)
+func (r *TradingService) SellStopCtx(ctx context.Context, order PendingOrder) (*Order, error) {
+	return r.sendPending(ctx, "TradingService.SellStop", OrderSellStop, order)
+}
+
+// BuyStopLimit วาง BuyLimit ที่ StopLimitPrice เมื่อราคาขึ้นถึง Price
+func (r *TradingService) BuyStopLimit(order PendingOrder) (*Order, error) {
+	return r.BuyStopLimitCtx(context.Background(), order)
+}
+
+// BuyStopLimitCtx วาง BuyLimit ที่ StopLimitPrice เมื่อราคาขึ้นถึง Price (รองรับ context)
+func (r *TradingService) BuyStopLimitCtx(ctx context.Context, order PendingOrder) (*Order, error) {
+	return r.sendPending(ctx, "TradingService.BuyStopLimit", OrderBuyStopLimit, order)
+}
+
+// SellStopLimit วาง SellLimit ที่ StopLimitPrice เมื่อราคาลงถึง Price
+func (r *TradingService) SellStopLimit(order PendingOrder) (*Order, error) {
+	return r.SellStopLimitCtx(context.Background(), order)
+}
+
+// SellStopLimitCtx วาง SellLimit ที่ StopLimitPrice เมื่อราคาลงถึง Price (รองรับ context)
+func (r *TradingService) SellStopLimitCtx(ctx context.Context, order PendingOrder) (*Order, error) {
+	return r.sendPending(ctx, "TradingService.SellStopLimit", OrderSellStopLimit, order)
+}
+
+// sendPending ตรวจสอบ pending order กับค่าตั้งของสัญลักษณ์แล้วส่ง
+func (r *TradingService) sendPending(ctx context.Context, spanName string, orderType OrderType, order PendingOrder) (*Order, error) {
+	ctx, span := r.client.startSpan(ctx, spanName, Attr("symbol", order.Symbol), Attr("volume", order.Volume), Attr("price", order.Price))
+	defer span.End()
+
+	req := OrderRequest{
+		Symbol:         order.Symbol,
+		Type:           orderType,
+		Volume:         order.Volume,
+		Price:          order.Price,
+		StopLimitPrice: order.StopLimitPrice,
+		StopLoss:       order.StopLoss,
+		TakeProfit:     order.TakeProfit,
+		Expiration:     order.Expiration,
+		Deviation:      order.Deviation,
+		FillPolicy:     order.FillPolicy,
+		Comment:        order.Comment,
+		MagicNumber:    order.MagicNumber,
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	// ตรวจ policy เฉพาะที่ผู้เรียกกำหนดเอง (ถ้าเปิด WithOrderValidation SendCtx จะตรวจให้อยู่แล้ว)
+	if r.client.orderValidator == nil && (req.Expiration != nil || req.FillPolicy != "") {
+		params, err := r.client.symbolParams.Params(ctx, order.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkSymbolPolicies(req, params.SymbolGroup); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.SendCtx(ctx, req)
+}
+
+// validate ตรวจสอบความสอดคล้องของ OrderRequest ก่อนส่ง (ไม่ต้องใช้ข้อมูลจาก server)
+func (req OrderRequest) validate() error {
+	if !req.Type.Valid() {
+		return fmt.Errorf("%w: unknown order type %q", ErrInvalidArgument, req.Type)
+	}
+	if req.Deviation < 0 {
+		return fmt.Errorf("%w: deviation must not be negative", ErrInvalidArgument)
+	}
+
+	isStopLimit := req.Type == OrderBuyStopLimit || req.Type == OrderSellStopLimit
+	switch {
+	case req.Type.IsPending() && req.Price <= 0:
+		return fmt.Errorf("%w: %s requires price", ErrInvalidArgument, req.Type)
+	case isStopLimit && req.StopLimitPrice <= 0:
+		return fmt.Errorf("%w: %s requires stop-limit price", ErrInvalidArgument, req.Type)
+	case !isStopLimit && req.StopLimitPrice > 0:
+		return fmt.Errorf("%w: stop-limit price is only valid for stop-limit orders", ErrInvalidArgument)
+	case isStopLimit && req.Type.IsLong() && req.StopLimitPrice > req.Price:
+		return fmt.Errorf("%w: BuyStopLimit limit price must not be above trigger price", ErrInvalidArgument)
+	case isStopLimit && req.Type.IsShort() && req.StopLimitPrice < req.Price:
+		return fmt.Errorf("%w: SellStopLimit limit price must not be below trigger price", ErrInvalidArgument)
+	}
+
+	if req.Expiration != nil {
+		if !req.Type.IsPending() {
+			return fmt.Errorf("%w: expiration is only valid for pending orders", ErrInvalidArgument)
+		}
+		switch req.Expiration.Mode {
+		case ExpirationGTC, ExpirationToday:
+		case ExpirationSpecified:
+			if req.Expiration.Time.IsZero() {
+				return fmt.Errorf("%w: specified expiration requires time", ErrInvalidArgument)
+			}
+		default:
+			return fmt.Errorf("%w: unknown expiration mode %q", ErrInvalidArgument, req.Expiration.Mode)
+		}
+	}
+
+	return nil
+}
+
+// checkSymbolPolicies ตรวจสอบ expiration และ fill policy กับ SymbolGroup
+// ค่าใน SymbolGroup เป็นรายการคั่นด้วย "," หรือ "|" ค่าว่าง/"All" หมายถึงอนุญาตทั้งหมด
+func checkSymbolPolicies(req OrderRequest, group SymbolGroup) error {
+	if req.Expiration != nil {
+		allowed := policySet(group.Expiration)
+		mode := req.Expiration.Mode
+		ok := allowed == nil || allowed[strings.ToLower(string(mode))] ||
+			(mode == ExpirationToday && allowed["day"])
+		if !ok {
+			return fmt.Errorf("%w: %s: expiration %s not allowed (symbol allows %s)", ErrInvalidArgument, req.Symbol, mode, group.Expiration)
+		}
+	}
+
+	if req.FillPolicy != "" {
+		if allowed := policySet(group.FillPolicy); allowed != nil && !allowed[strings.ToLower(string(req.FillPolicy))] {
+			return fmt.Errorf("%w: %s: fill policy %s not allowed (symbol allows %s)", ErrInvalidArgument, req.Symbol, req.FillPolicy, group.FillPolicy)
+		}
+	}
+
+	return nil
+}
+
+// policySet แยกรายการค่าที่อนุญาต (nil = อนุญาตทั้งหมด)
+func policySet(value string) map[string]bool {
+	set := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '|' || r == ' ' }) {
+		field = strings.ToLower(field)
+		if field == "all" || field == "any" {
+			return nil
+		}
+		set[field] = true
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	return set
+}
@@ -0,0 +1,154 @@
+package mt5client_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ditthkr/mt5client"
+	"github.com/ditthkr/mt5client/mt5test"
+)
+
+// newPendingGateway gateway ที่ EURUSD อนุญาตเฉพาะ GTC/Today และ FillOrKill
+func newPendingGateway(t *testing.T, opts ...mt5client.Option) (*mt5test.Server, *mt5client.Client) {
+	t.Helper()
+
+	srv, client := newGateway(t, opts...)
+	params := mt5test.ForexSymbol("EURUSD", 5)
+	params.SymbolGroup.Expiration = "GTC,Today"
+	srv.AddSymbol(params)
+	return srv, client
+}
+
+func TestBuyStopLimitSendsAllParameters(t *testing.T) {
+	srv, client := newPendingGateway(t)
+
+	order, err := client.Trading.BuyStopLimit(mt5client.PendingOrder{
+		Symbol:         "EURUSD",
+		Volume:         0.1,
+		Price:          1.1050,
+		StopLimitPrice: 1.1040,
+		Expiration:     mt5client.ExpireToday(),
+		Deviation:      10,
+		FillPolicy:     mt5client.FillOrKill,
+	})
+	if err != nil {
+		t.Fatalf("BuyStopLimit failed: %v", err)
+	}
+	if order.OrderType != mt5client.OrderBuyStopLimit {
+		t.Errorf("expected BuyStopLimit, got %s", order.OrderType)
+	}
+
+	sent := srv.LastQuery("/OrderSend")
+	want := map[string]string{
+		"operation":      "BuyStopLimit",
+		"price":          "1.10500",
+		"stoplimit":      "1.10400",
+		"expirationType": "Today",
+		"slippage":       "10",
+		"fillPolicy":     "FillOrKill",
+	}
+	for key, value := range want {
+		if got := sent.Get(key); got != value {
+			t.Errorf("%s: expected %q, got %q", key, value, got)
+		}
+	}
+}
+
+func TestPendingOrderLeavesExpirationToServer(t *testing.T) {
+	srv, client := newGateway(t)
+	params := mt5test.ForexSymbol("EURUSD", 5)
+	params.SymbolGroup.Expiration = "Today,Specified"
+	srv.AddSymbol(params)
+
+	if _, err := client.Trading.SellLimit(mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1, Price: 1.2}); err != nil {
+		t.Fatalf("SellLimit failed: %v", err)
+	}
+	if sent := srv.LastQuery("/OrderSend"); sent.Has("expirationType") {
+		t.Errorf("expected no expirationType, got %q", sent.Get("expirationType"))
+	}
+	if n := srv.RequestCount("/SymbolParams"); n != 0 {
+		t.Errorf("expected no SymbolParams lookup without explicit policies, got %d", n)
+	}
+}
+
+func TestPendingOrderCachesSymbolParams(t *testing.T) {
+	srv, client := newPendingGateway(t)
+
+	for i := 0; i < 2; i++ {
+		order := mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1, Price: 1.1, Expiration: mt5client.ExpireToday(), FillPolicy: mt5client.FillOrKill}
+		if _, err := client.Trading.BuyLimit(order); err != nil {
+			t.Fatalf("BuyLimit failed: %v", err)
+		}
+	}
+	if n := srv.RequestCount("/SymbolParams"); n != 1 {
+		t.Errorf("expected 1 SymbolParams lookup, got %d", n)
+	}
+}
+
+func TestPendingOrderValidation(t *testing.T) {
+	srv, client := newPendingGateway(t)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		send  func(mt5client.PendingOrder) (*mt5client.Order, error)
+		order mt5client.PendingOrder
+	}{
+		{"missing price", client.Trading.BuyLimit, mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1}},
+		{"missing stop-limit price", client.Trading.SellStopLimit, mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1, Price: 1.1}},
+		{"stop-limit price on stop order", client.Trading.BuyStop, mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1, Price: 1.1, StopLimitPrice: 1.0}},
+		{"buy stop-limit above trigger", client.Trading.BuyStopLimit, mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1, Price: 1.1, StopLimitPrice: 1.2}},
+		{"specified without time", client.Trading.SellStop, mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1, Price: 1.1, Expiration: &mt5client.Expiration{Mode: mt5client.ExpirationSpecified}}},
+		{"expiration not allowed", client.Trading.SellStop, mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1, Price: 1.1, Expiration: mt5client.ExpireAt(future)}},
+		{"fill policy not allowed", client.Trading.BuyLimit, mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1, Price: 1.1, FillPolicy: mt5client.FillImmediateOrCancel}},
+		{"negative deviation", client.Trading.BuyLimit, mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1, Price: 1.1, Deviation: -1}},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.send(tt.order); !errors.Is(err, mt5client.ErrInvalidArgument) {
+			t.Errorf("%s: expected ErrInvalidArgument, got %v", tt.name, err)
+		}
+	}
+	if n := srv.RequestCount("/OrderSend"); n != 0 {
+		t.Errorf("expected no order sent despite validation errors, got %d", n)
+	}
+}
+
+func TestSendRejectsExpirationOnMarketOrder(t *testing.T) {
+	srv, client := newGateway(t)
+
+	_, err := client.Trading.Send(mt5client.OrderRequest{Symbol: "EURUSD", Type: mt5client.OrderBuy, Volume: 0.1, Expiration: mt5client.ExpireToday()})
+	if !errors.Is(err, mt5client.ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument, got %v", err)
+	}
+	if n := srv.RequestCount("/OrderSend"); n != 0 {
+		t.Errorf("expected no send, got %d", n)
+	}
+}
+
+func TestSpecifiedExpirationUsesServerWallClock(t *testing.T) {
+	srv, client := newGateway(t)
+
+	bangkok := time.FixedZone("ICT", 7*3600)
+	serverZone := time.FixedZone("EET", 2*3600)
+	local := time.Date(2026, 3, 10, 18, 30, 0, 0, bangkok)
+
+	tests := []struct {
+		name       string
+		expiration *mt5client.Expiration
+		want       string
+	}{
+		{"wall clock is sent as is", mt5client.ExpireAt(local), "2026-03-10T18:30:00"},
+		{"converted to server location", mt5client.ExpireAtIn(local, serverZone), "2026-03-10T13:30:00"},
+	}
+	for _, tt := range tests {
+		_, err := client.Trading.BuyLimit(mt5client.PendingOrder{Symbol: "EURUSD", Volume: 0.1, Price: 1.1, Expiration: tt.expiration})
+		if err != nil {
+			t.Fatalf("%s: BuyLimit failed: %v", tt.name, err)
+		}
+		if got := srv.LastQuery("/OrderSend").Get("expiration"); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
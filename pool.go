@@ -0,0 +1,276 @@
+package mt5client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// PooledAccount บัญชีหนึ่งใน AccountPool (มี Client และ Session ของตัวเอง)
+type PooledAccount struct {
+	Login    int64
+	Client   *Client
+	Session  *Session
+	provider CredentialProvider
+
+	mu     sync.Mutex
+	ws     *WebSocketClient
+	health *HealthMonitor
+}
+
+// WebSocket คืน WebSocketClient ของบัญชี (สร้างครั้งแรกที่เรียก และได้ token ใหม่เมื่อ re-login)
+func (a *PooledAccount) WebSocket() *WebSocketClient {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.ws == nil {
+		a.ws = a.Session.NewWebSocketClient()
+	}
+	return a.ws
+}
+
+// Health คืน HealthMonitor ของบัญชี (nil ถ้ายังไม่ได้เรียก AccountPool.StartHealth)
+func (a *PooledAccount) Health() *HealthMonitor {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.health
+}
+
+// AccountPool จัดการหลายบัญชีพร้อมกัน โดยแต่ละบัญชีมี Client/Session แยกกัน (ใช้จากหลาย goroutine ได้)
+//
+//	pool := mt5client.NewAccountPool("http://localhost:5000", mt5client.WithTimeout(10*time.Second))
+//	if _, err := pool.Add(1001, mt5client.FileCredentials("accounts/1001.json")); err != nil { ... }
+//	if _, err := pool.Add(1002, mt5client.FileCredentials("accounts/1002.json")); err != nil { ... }
+//	errs := pool.ConnectAll(ctx)
+//	infos, errs := pool.GetInfoAll(ctx)
+type AccountPool struct {
+	baseURL string
+	opts    []Option
+
+	mu          sync.RWMutex
+	accounts    map[int64]*PooledAccount
+	maxParallel int
+}
+
+// NewAccountPool สร้าง AccountPool โดย opts จะใช้กับ Client ของทุกบัญชี
+func NewAccountPool(baseURL string, opts ...Option) *AccountPool {
+	return &AccountPool{
+		baseURL:     baseURL,
+		opts:        opts,
+		accounts:    make(map[int64]*PooledAccount),
+		maxParallel: 8,
+	}
+}
+
+// SetMaxParallel จำนวนบัญชีสูงสุดที่ทำงานพร้อมกันใน fan-out (default: 8)
+func (p *AccountPool) SetMaxParallel(n int) {
+	if n < 1 {
+		n = 1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxParallel = n
+}
+
+// Add เพิ่มบัญชี (ยังไม่เชื่อมต่อ) ถ้ามี login นี้อยู่แล้วจะคืนตัวเดิม
+// provider ต้องไม่เป็น nil และต้องคืน Credentials.User ตรงกับ login (ตรวจตอนเชื่อมต่อ)
+func (p *AccountPool) Add(login int64, provider CredentialProvider) (*PooledAccount, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("%w: login %d: credential provider is nil", ErrInvalidArgument, login)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if account, ok := p.accounts[login]; ok {
+		return account, nil
+	}
+
+	client := NewClient(p.baseURL, p.opts...)
+	account := &PooledAccount{
+		Login:    login,
+		Client:   client,
+		Session:  NewSession(client),
+		provider: loginProvider{login: login, provider: provider},
+	}
+	p.accounts[login] = account
+	return account, nil
+}
+
+// loginProvider ตรวจว่า Credentials ที่ได้เป็นของ login ที่ใช้เป็น key ใน pool
+// (ใช้ทั้งตอน ConnectAll และตอน Session re-login)
+type loginProvider struct {
+	login    int64
+	provider CredentialProvider
+}
+
+// Credentials implements CredentialProvider
+func (p loginProvider) Credentials(ctx context.Context) (Credentials, error) {
+	creds, err := p.provider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if creds.User != p.login {
+		return Credentials{}, fmt.Errorf("%w: credentials are for login %d, expected %d", ErrInvalidArgument, creds.User, p.login)
+	}
+	return creds, nil
+}
+
+// Get คืนบัญชีตาม login
+func (p *AccountPool) Get(login int64) (*PooledAccount, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	account, ok := p.accounts[login]
+	return account, ok
+}
+
+// Logins คืน login ทั้งหมด (เรียงจากน้อยไปมาก)
+func (p *AccountPool) Logins() []int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	logins := make([]int64, 0, len(p.accounts))
+	for login := range p.accounts {
+		logins = append(logins, login)
+	}
+	sort.Slice(logins, func(i, j int) bool { return logins[i] < logins[j] })
+	return logins
+}
+
+// Remove ตัดการเชื่อมต่อและนำบัญชีออกจาก pool
+func (p *AccountPool) Remove(ctx context.Context, login int64) error {
+	p.mu.Lock()
+	account, ok := p.accounts[login]
+	delete(p.accounts, login)
+	p.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: login %d is not in pool", ErrInvalidArgument, login)
+	}
+	return account.close(ctx)
+}
+
+// ForEach เรียก fn กับทุกบัญชีแบบขนาน (ไม่เกิน SetMaxParallel) คืน error ของบัญชีที่ล้มเหลว
+func (p *AccountPool) ForEach(ctx context.Context, fn func(ctx context.Context, account *PooledAccount) error) map[int64]error {
+	p.mu.RLock()
+	accounts := make([]*PooledAccount, 0, len(p.accounts))
+	for _, account := range p.accounts {
+		accounts = append(accounts, account)
+	}
+	sem := make(chan struct{}, p.maxParallel)
+	p.mu.RUnlock()
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[int64]error)
+	)
+	for _, account := range accounts {
+		wg.Add(1)
+		go func(account *PooledAccount) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				mu.Lock()
+				errs[account.Login] = ctx.Err()
+				mu.Unlock()
+				return
+			}
+
+			if err := fn(ctx, account); err != nil {
+				mu.Lock()
+				errs[account.Login] = err
+				mu.Unlock()
+			}
+		}(account)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// ConnectAll เชื่อมต่อทุกบัญชีผ่าน Session (re-login อัตโนมัติเมื่อ token หมดอายุ)
+func (p *AccountPool) ConnectAll(ctx context.Context) map[int64]error {
+	return p.ForEach(ctx, func(ctx context.Context, account *PooledAccount) error {
+		_, err := account.Session.ConnectWithCtx(ctx, account.provider)
+		return err
+	})
+}
+
+// DisconnectAll ตัดการเชื่อมต่อทุกบัญชี (รวม WebSocket และ HealthMonitor) แต่ยังเก็บบัญชีไว้ใน pool
+func (p *AccountPool) DisconnectAll(ctx context.Context) map[int64]error {
+	return p.ForEach(ctx, func(ctx context.Context, account *PooledAccount) error {
+		return account.close(ctx)
+	})
+}
+
+// GetInfoAll ดึง Account.GetInfo ของทุกบัญชีแบบขนาน
+func (p *AccountPool) GetInfoAll(ctx context.Context) (map[int64]*Account, map[int64]error) {
+	var mu sync.Mutex
+	infos := make(map[int64]*Account)
+
+	errs := p.ForEach(ctx, func(ctx context.Context, account *PooledAccount) error {
+		info, err := account.Client.Account.GetInfoCtx(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		infos[account.Login] = info
+		mu.Unlock()
+		return nil
+	})
+
+	return infos, errs
+}
+
+// StartHealth เริ่ม HealthMonitor ของทุกบัญชี (re-login ผ่าน Session ของบัญชีเมื่อหลุด)
+func (p *AccountPool) StartHealth(ctx context.Context, config HealthConfig) {
+	for _, login := range p.Logins() {
+		account, ok := p.Get(login)
+		if !ok {
+			continue
+		}
+
+		account.mu.Lock()
+		if account.health == nil {
+			cfg := config
+			cfg.Session = account.Session
+			account.health = NewHealthMonitor(account.Client, cfg)
+			account.health.Start(ctx)
+		}
+		account.mu.Unlock()
+	}
+}
+
+// States คืนสถานะจาก HealthMonitor ของทุกบัญชีที่เริ่ม StartHealth แล้ว
+func (p *AccountPool) States() map[int64]ConnectionState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	states := make(map[int64]ConnectionState, len(p.accounts))
+	for login, account := range p.accounts {
+		if health := account.Health(); health != nil {
+			states[login] = health.State()
+		}
+	}
+	return states
+}
+
+// close หยุด HealthMonitor, ปิด WebSocket และ Disconnect
+func (a *PooledAccount) close(ctx context.Context) error {
+	a.mu.Lock()
+	health, ws := a.health, a.ws
+	a.health = nil
+	a.mu.Unlock()
+
+	if health != nil {
+		health.Stop()
+	}
+	if ws != nil {
+		ws.Disconnect()
+	}
+	return a.Session.DisconnectCtx(ctx)
+}
@@ -0,0 +1,116 @@
+package mt5client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ditthkr/mt5client"
+	"github.com/ditthkr/mt5client/mt5test"
+)
+
+// newPoolGateway gateway ที่มีบัญชีตาม logins (รหัสผ่าน "secret", balance = login * 100)
+func newPoolGateway(t *testing.T, logins ...int64) *mt5test.Server {
+	t.Helper()
+
+	srv := mt5test.NewServer()
+	t.Cleanup(srv.Close)
+
+	for _, login := range logins {
+		srv.AddAccount(mt5client.Account{Login: login, Balance: float64(login * 100), Currency: "USD", Server: "Demo-Server"}, "secret")
+	}
+	return srv
+}
+
+func poolCredentials(login int64, password string) mt5client.CredentialProvider {
+	return mt5client.CredentialFunc(func(ctx context.Context) (mt5client.Credentials, error) {
+		return mt5client.Credentials{User: login, Password: password, Server: "Demo-Server"}, nil
+	})
+}
+
+func TestAccountPoolFanOut(t *testing.T) {
+	srv := newPoolGateway(t, 1001, 1002, 1003, 1004)
+	ctx := context.Background()
+
+	pool := mt5client.NewAccountPool(srv.URL, mt5client.WithLogger(nil))
+	for _, login := range []int64{1003, 1001, 1002} {
+		if _, err := pool.Add(login, poolCredentials(login, "secret")); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if _, err := pool.Add(1004, poolCredentials(1004, "wrong")); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	errs := pool.ConnectAll(ctx)
+	if len(errs) != 1 || errs[1004] == nil {
+		t.Fatalf("expected only 1004 to fail, got %v", errs)
+	}
+
+	infos, errs := pool.GetInfoAll(ctx)
+	if len(infos) != 3 {
+		t.Fatalf("expected 3 infos, got %d (errors: %v)", len(infos), errs)
+	}
+	for login, info := range infos {
+		if info.Login != login || info.Balance != float64(login*100) {
+			t.Errorf("account %d returned info for %d (balance %g)", login, info.Login, info.Balance)
+		}
+	}
+	if _, ok := errs[1004]; !ok {
+		t.Error("expected GetInfo error for disconnected account 1004")
+	}
+
+	if got := pool.Logins(); fmt.Sprint(got) != "[1001 1002 1003 1004]" {
+		t.Errorf("unexpected logins %v", got)
+	}
+
+	account, ok := pool.Get(1001)
+	if !ok || account.Client.GetToken() == "" {
+		t.Fatalf("expected account 1001 to be connected")
+	}
+	other, _ := pool.Get(1002)
+	if account.Client.GetToken() == other.Client.GetToken() {
+		t.Error("expected each account to have its own token")
+	}
+	if account.WebSocket() != account.WebSocket() {
+		t.Error("expected the same WebSocket client on each call")
+	}
+
+	if errs := pool.DisconnectAll(ctx); len(errs) != 0 {
+		t.Errorf("DisconnectAll failed: %v", errs)
+	}
+	if account.Client.GetToken() != "" {
+		t.Error("expected token to be cleared after DisconnectAll")
+	}
+
+	if err := pool.Remove(ctx, 1001); err != nil {
+		t.Errorf("Remove failed: %v", err)
+	}
+	if _, ok := pool.Get(1001); ok {
+		t.Error("expected 1001 to be removed")
+	}
+}
+
+func TestAccountPoolRejectsBadProviders(t *testing.T) {
+	srv := newPoolGateway(t, 1002, 2002)
+	pool := mt5client.NewAccountPool(srv.URL, mt5client.WithLogger(nil))
+
+	if _, err := pool.Add(1001, nil); !errors.Is(err, mt5client.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument for nil provider, got %v", err)
+	}
+	if _, ok := pool.Get(1001); ok {
+		t.Error("expected nil provider not to be added")
+	}
+
+	if _, err := pool.Add(1002, poolCredentials(2002, "secret")); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	errs := pool.ConnectAll(context.Background())
+	if !errors.Is(errs[1002], mt5client.ErrInvalidArgument) {
+		t.Fatalf("expected login mismatch error, got %v", errs[1002])
+	}
+	if n := srv.RequestCount("/ConnectEx"); n != 0 {
+		t.Errorf("expected no login with mismatched credentials, got %d", n)
+	}
+}
@@ -1,6 +1,9 @@
 package mt5client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // PriceService จัดการข้อมูลราคา
 type PriceService struct {
@@ -9,19 +12,28 @@ type PriceService struct {
 
 // GetHistory ดึงประวัติราคา
 func (r *PriceService) GetHistory(symbol, timeframe string, count int) ([]Bar, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetHistoryCtx(context.Background(), symbol, timeframe, count)
+}
+
+// GetHistoryCtx ดึงประวัติราคา (รองรับ context)
+func (r *PriceService) GetHistoryCtx(ctx context.Context, symbol, timeframe string, count int) ([]Bar, error) {
+	ctx, span := r.client.startSpan(ctx, "PriceService.GetHistory", Attr("symbol", symbol), Attr("timeframe", timeframe))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
+		"id":        token,
 		"symbol":    symbol,
 		"timeframe": timeframe,
 		"count":     fmt.Sprintf("%d", count),
 	}
 
 	var bars []Bar
-	err := r.client.get("/PriceHistory", queryParams, &bars)
+	err := r.client.get(ctx, "/PriceHistory", queryParams, &bars)
 	if err != nil {
 		return nil, err
 	}
@@ -31,12 +43,21 @@ func (r *PriceService) GetHistory(symbol, timeframe string, count int) ([]Bar, e
 
 // GetHistoryEx ดึงประวัติราคาแบบ Extended
 func (r *PriceService) GetHistoryEx(symbol, timeframe, from, to string) ([]Bar, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetHistoryExCtx(context.Background(), symbol, timeframe, from, to)
+}
+
+// GetHistoryExCtx ดึงประวัติราคาแบบ Extended (รองรับ context)
+func (r *PriceService) GetHistoryExCtx(ctx context.Context, symbol, timeframe, from, to string) ([]Bar, error) {
+	ctx, span := r.client.startSpan(ctx, "PriceService.GetHistoryEx", Attr("symbol", symbol), Attr("timeframe", timeframe), Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
+		"id":        token,
 		"symbol":    symbol,
 		"timeframe": timeframe,
 		"from":      from,
@@ -44,7 +65,7 @@ func (r *PriceService) GetHistoryEx(symbol, timeframe, from, to string) ([]Bar,
 	}
 
 	var bars []Bar
-	err := r.client.get("/PriceHistoryEx", queryParams, &bars)
+	err := r.client.get(ctx, "/PriceHistoryEx", queryParams, &bars)
 	if err != nil {
 		return nil, err
 	}
@@ -54,12 +75,21 @@ func (r *PriceService) GetHistoryEx(symbol, timeframe, from, to string) ([]Bar,
 
 // GetHistoryMany ดึงประวัติหลายสัญลักษณ์
 func (r *PriceService) GetHistoryMany(symbols []string, timeframe string, count int) (map[string][]Bar, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetHistoryManyCtx(context.Background(), symbols, timeframe, count)
+}
+
+// GetHistoryManyCtx ดึงประวัติหลายสัญลักษณ์ (รองรับ context)
+func (r *PriceService) GetHistoryManyCtx(ctx context.Context, symbols []string, timeframe string, count int) (map[string][]Bar, error) {
+	ctx, span := r.client.startSpan(ctx, "PriceService.GetHistoryMany", Attr("symbols", symbols), Attr("timeframe", timeframe))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
+		"id":        token,
 		"timeframe": timeframe,
 		"count":     fmt.Sprintf("%d", count),
 	}
@@ -69,7 +99,7 @@ func (r *PriceService) GetHistoryMany(symbols []string, timeframe string, count
 	}
 
 	var result map[string][]Bar
-	err := r.client.get("/PriceHistoryMany", queryParams, &result)
+	err := r.client.get(ctx, "/PriceHistoryMany", queryParams, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -79,12 +109,21 @@ func (r *PriceService) GetHistoryMany(symbols []string, timeframe string, count
 
 // GetHistoryExMany ดึงประวัติแบบ Extended หลายสัญลักษณ์
 func (r *PriceService) GetHistoryExMany(symbols []string, timeframe, from, to string) (map[string][]Bar, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetHistoryExManyCtx(context.Background(), symbols, timeframe, from, to)
+}
+
+// GetHistoryExManyCtx ดึงประวัติแบบ Extended หลายสัญลักษณ์ (รองรับ context)
+func (r *PriceService) GetHistoryExManyCtx(ctx context.Context, symbols []string, timeframe, from, to string) (map[string][]Bar, error) {
+	ctx, span := r.client.startSpan(ctx, "PriceService.GetHistoryExMany", Attr("symbols", symbols), Attr("timeframe", timeframe), Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
+		"id":        token,
 		"timeframe": timeframe,
 		"from":      from,
 		"to":        to,
@@ -95,7 +134,7 @@ func (r *PriceService) GetHistoryExMany(symbols []string, timeframe, from, to st
 	}
 
 	var result map[string][]Bar
-	err := r.client.get("/PriceHistoryExMany", queryParams, &result)
+	err := r.client.get(ctx, "/PriceHistoryExMany", queryParams, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -105,19 +144,28 @@ func (r *PriceService) GetHistoryExMany(symbols []string, timeframe, from, to st
 
 // GetHistoryHighLow ดึง High/Low ในช่วงเวลา
 func (r *PriceService) GetHistoryHighLow(symbol, from, to string) (map[string]float64, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetHistoryHighLowCtx(context.Background(), symbol, from, to)
+}
+
+// GetHistoryHighLowCtx ดึง High/Low ในช่วงเวลา (รองรับ context)
+func (r *PriceService) GetHistoryHighLowCtx(ctx context.Context, symbol, from, to string) (map[string]float64, error) {
+	ctx, span := r.client.startSpan(ctx, "PriceService.GetHistoryHighLow", Attr("symbol", symbol), Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 		"from":   from,
 		"to":     to,
 	}
 
 	var result map[string]float64
-	err := r.client.get("/PriceHistoryHighLow", queryParams, &result)
+	err := r.client.get(ctx, "/PriceHistoryHighLow", queryParams, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -127,18 +175,27 @@ func (r *PriceService) GetHistoryHighLow(symbol, from, to string) (map[string]fl
 
 // GetHistoryToday ดึงประวัติวันนี้
 func (r *PriceService) GetHistoryToday(symbol, timeframe string) ([]Bar, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetHistoryTodayCtx(context.Background(), symbol, timeframe)
+}
+
+// GetHistoryTodayCtx ดึงประวัติวันนี้ (รองรับ context)
+func (r *PriceService) GetHistoryTodayCtx(ctx context.Context, symbol, timeframe string) ([]Bar, error) {
+	ctx, span := r.client.startSpan(ctx, "PriceService.GetHistoryToday", Attr("symbol", symbol), Attr("timeframe", timeframe))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
+		"id":        token,
 		"symbol":    symbol,
 		"timeframe": timeframe,
 	}
 
 	var bars []Bar
-	err := r.client.get("/PriceHistoryToday", queryParams, &bars)
+	err := r.client.get(ctx, "/PriceHistoryToday", queryParams, &bars)
 	if err != nil {
 		return nil, err
 	}
@@ -148,12 +205,21 @@ func (r *PriceService) GetHistoryToday(symbol, timeframe string) ([]Bar, error)
 
 // GetHistoryTodayMany ดึงประวัติวันนี้หลายสัญลักษณ์
 func (r *PriceService) GetHistoryTodayMany(symbols []string, timeframe string) (map[string][]Bar, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetHistoryTodayManyCtx(context.Background(), symbols, timeframe)
+}
+
+// GetHistoryTodayManyCtx ดึงประวัติวันนี้หลายสัญลักษณ์ (รองรับ context)
+func (r *PriceService) GetHistoryTodayManyCtx(ctx context.Context, symbols []string, timeframe string) (map[string][]Bar, error) {
+	ctx, span := r.client.startSpan(ctx, "PriceService.GetHistoryTodayMany", Attr("symbols", symbols), Attr("timeframe", timeframe))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
+		"id":        token,
 		"timeframe": timeframe,
 	}
 
@@ -162,7 +228,7 @@ func (r *PriceService) GetHistoryTodayMany(symbols []string, timeframe string) (
 	}
 
 	var result map[string][]Bar
-	err := r.client.get("/PriceHistoryTodayMany", queryParams, &result)
+	err := r.client.get(ctx, "/PriceHistoryTodayMany", queryParams, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -172,12 +238,21 @@ func (r *PriceService) GetHistoryTodayMany(symbols []string, timeframe string) (
 
 // GetHistoryMonth ดึงประวัติรายเดือน
 func (r *PriceService) GetHistoryMonth(symbol, timeframe string, year, month int) ([]Bar, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetHistoryMonthCtx(context.Background(), symbol, timeframe, year, month)
+}
+
+// GetHistoryMonthCtx ดึงประวัติรายเดือน (รองรับ context)
+func (r *PriceService) GetHistoryMonthCtx(ctx context.Context, symbol, timeframe string, year, month int) ([]Bar, error) {
+	ctx, span := r.client.startSpan(ctx, "PriceService.GetHistoryMonth", Attr("symbol", symbol), Attr("timeframe", timeframe))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
+		"id":        token,
 		"symbol":    symbol,
 		"timeframe": timeframe,
 		"year":      fmt.Sprintf("%d", year),
@@ -185,7 +260,7 @@ func (r *PriceService) GetHistoryMonth(symbol, timeframe string, year, month int
 	}
 
 	var bars []Bar
-	err := r.client.get("/PriceHistoryMonth", queryParams, &bars)
+	err := r.client.get(ctx, "/PriceHistoryMonth", queryParams, &bars)
 	if err != nil {
 		return nil, err
 	}
@@ -195,12 +270,21 @@ func (r *PriceService) GetHistoryMonth(symbol, timeframe string, year, month int
 
 // GetHistoryMonthMany ดึงประวัติรายเดือนหลายสัญลักษณ์
 func (r *PriceService) GetHistoryMonthMany(symbols []string, timeframe string, year, month int) (map[string][]Bar, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetHistoryMonthManyCtx(context.Background(), symbols, timeframe, year, month)
+}
+
+// GetHistoryMonthManyCtx ดึงประวัติรายเดือนหลายสัญลักษณ์ (รองรับ context)
+func (r *PriceService) GetHistoryMonthManyCtx(ctx context.Context, symbols []string, timeframe string, year, month int) (map[string][]Bar, error) {
+	ctx, span := r.client.startSpan(ctx, "PriceService.GetHistoryMonthMany", Attr("symbols", symbols), Attr("timeframe", timeframe))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
+		"id":        token,
 		"timeframe": timeframe,
 		"year":      fmt.Sprintf("%d", year),
 		"month":     fmt.Sprintf("%d", month),
@@ -211,7 +295,7 @@ func (r *PriceService) GetHistoryMonthMany(symbols []string, timeframe string, y
 	}
 
 	var result map[string][]Bar
-	err := r.client.get("/PriceHistoryMonthMany", queryParams, &result)
+	err := r.client.get(ctx, "/PriceHistoryMonthMany", queryParams, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -221,33 +305,51 @@ func (r *PriceService) GetHistoryMonthMany(symbols []string, timeframe string, y
 
 // RequestTickHistory ขอประวัติ tick
 func (r *PriceService) RequestTickHistory(symbol, from, to string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.RequestTickHistoryCtx(context.Background(), symbol, from, to)
+}
+
+// RequestTickHistoryCtx ขอประวัติ tick (รองรับ context)
+func (r *PriceService) RequestTickHistoryCtx(ctx context.Context, symbol, from, to string) error {
+	ctx, span := r.client.startSpan(ctx, "PriceService.RequestTickHistory", Attr("symbol", symbol), Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 		"from":   from,
 		"to":     to,
 	}
 
 	var result string
-	err := r.client.get("/TickHistoryRequest", queryParams, &result)
+	err := r.client.get(ctx, "/TickHistoryRequest", queryParams, &result)
 	return err
 }
 
 // StopTickHistory หยุดการขอประวัติ tick
 func (r *PriceService) StopTickHistory() error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.StopTickHistoryCtx(context.Background())
+}
+
+// StopTickHistoryCtx หยุดการขอประวัติ tick (รองรับ context)
+func (r *PriceService) StopTickHistoryCtx(ctx context.Context) error {
+	ctx, span := r.client.startSpan(ctx, "PriceService.StopTickHistory")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var result string
-	err := r.client.get("/TickHistoryStop", queryParams, &result)
+	err := r.client.get(ctx, "/TickHistoryStop", queryParams, &result)
 	return err
 }
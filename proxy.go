@@ -0,0 +1,84 @@
+package mt5client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProxyType ชนิดของ proxy ที่ ConnectProxy รองรับ
+type ProxyType string
+
+const (
+	ProxySOCKS4 ProxyType = "Socks4"
+	ProxySOCKS5 ProxyType = "Socks5"
+	ProxyHTTP   ProxyType = "Http"
+)
+
+// ParseProxyType แปลงชื่อ proxy (ไม่สนตัวพิมพ์เล็กใหญ่ เช่น "socks5", "SOCKS5", "http")
+func ParseProxyType(s string) (ProxyType, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "socks4":
+		return ProxySOCKS4, nil
+	case "socks5":
+		return ProxySOCKS5, nil
+	case "http", "https":
+		return ProxyHTTP, nil
+	default:
+		return "", fmt.Errorf("%w: unknown proxy type %q", ErrInvalidArgument, s)
+	}
+}
+
+// Valid ตรวจสอบว่าเป็นชนิดที่รองรับ
+func (t ProxyType) Valid() bool {
+	return t == ProxySOCKS4 || t == ProxySOCKS5 || t == ProxyHTTP
+}
+
+// ProxyConfig ค่าตั้ง proxy สำหรับ ConnectViaProxy
+type ProxyConfig struct {
+	Type     ProxyType `json:"type"`
+	Host     string    `json:"host"`
+	Port     int       `json:"port"`
+	User     string    `json:"user,omitempty"`     // ไม่บังคับ
+	Password string    `json:"password,omitempty"` // ไม่บังคับ
+}
+
+// String ไม่แสดงรหัสผ่านของ proxy
+func (p ProxyConfig) String() string {
+	return fmt.Sprintf("%s://%s:%d", p.Type, p.Host, p.Port)
+}
+
+// Validate ตรวจสอบค่าตั้งก่อนส่งไปยัง gateway
+func (p ProxyConfig) Validate() error {
+	if !p.Type.Valid() {
+		return fmt.Errorf("%w: unsupported proxy type %q", ErrInvalidArgument, p.Type)
+	}
+	if p.Host == "" {
+		return fmt.Errorf("%w: proxy host is required", ErrInvalidArgument)
+	}
+	if p.Port < 1 || p.Port > 65535 {
+		return fmt.Errorf("%w: proxy port %d out of range", ErrInvalidArgument, p.Port)
+	}
+	if p.Password != "" && p.User == "" {
+		return fmt.Errorf("%w: proxy password requires user", ErrInvalidArgument)
+	}
+	if p.Type == ProxySOCKS4 && p.Password != "" {
+		return fmt.Errorf("%w: SOCKS4 proxy does not support password", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// queryParams พารามิเตอร์ proxy สำหรับ /ConnectProxy
+func (p ProxyConfig) queryParams() map[string]string {
+	params := map[string]string{
+		"proxyType": string(p.Type),
+		"proxyHost": p.Host,
+		"proxyPort": fmt.Sprintf("%d", p.Port),
+	}
+	if p.User != "" {
+		params["proxyUser"] = p.User
+	}
+	if p.Password != "" {
+		params["proxyPassword"] = p.Password
+	}
+	return params
+}
@@ -0,0 +1,130 @@
+package mt5client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestParseProxyType(t *testing.T) {
+	tests := map[string]ProxyType{"socks4": ProxySOCKS4, "SOCKS5": ProxySOCKS5, "Http": ProxyHTTP}
+	for input, want := range tests {
+		got, err := ParseProxyType(input)
+		if err != nil || got != want {
+			t.Errorf("ParseProxyType(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := ParseProxyType("ftp"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestProxyConfigValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		proxy ProxyConfig
+		ok    bool
+	}{
+		{"socks5", ProxyConfig{Type: ProxySOCKS5, Host: "proxy", Port: 1080}, true},
+		{"http with credentials", ProxyConfig{Type: ProxyHTTP, Host: "proxy", Port: 8080, User: "u", Password: "p"}, true},
+		{"unknown type", ProxyConfig{Type: "ftp", Host: "proxy", Port: 21}, false},
+		{"missing host", ProxyConfig{Type: ProxySOCKS5, Port: 1080}, false},
+		{"bad port", ProxyConfig{Type: ProxySOCKS5, Host: "proxy", Port: 70000}, false},
+		{"password without user", ProxyConfig{Type: ProxyHTTP, Host: "proxy", Port: 8080, Password: "p"}, false},
+		{"socks4 with password", ProxyConfig{Type: ProxySOCKS4, Host: "proxy", Port: 1080, User: "u", Password: "p"}, false},
+	}
+
+	for _, tt := range tests {
+		err := tt.proxy.Validate()
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%s: expected ErrInvalidArgument, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestSessionReconnectsThroughProxy(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		connects []url.Values
+		token    string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/ConnectProxy":
+			connects = append(connects, r.URL.Query())
+			token = fmt.Sprintf("token-%d", len(connects))
+			w.Write([]byte(token))
+		case "/Account":
+			if r.URL.Query().Get("id") != token {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":"INVALID_TOKEN","message":"Invalid token"}`))
+				return
+			}
+			w.Write([]byte(`{"login":1001}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	session := NewSession(client)
+	proxy := ProxyConfig{Type: ProxySOCKS5, Host: "proxy.local", Port: 1080, User: "u", Password: "proxy-secret"}
+
+	if _, err := session.ConnectViaProxy(ConnectParams{User: 1001, Password: "p", Host: "mt5.local", Port: 443}, proxy); err != nil {
+		t.Fatalf("ConnectViaProxy failed: %v", err)
+	}
+
+	mu.Lock()
+	token = "expired"
+	mu.Unlock()
+
+	if _, err := client.Account.GetInfo(); err != nil {
+		t.Fatalf("GetInfo failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(connects) != 2 {
+		t.Fatalf("expected re-login through proxy, got %d connects", len(connects))
+	}
+	for _, q := range connects {
+		if q.Get("proxyType") != "Socks5" || q.Get("proxyHost") != "proxy.local" || q.Get("proxyPort") != "1080" || q.Get("proxyUser") != "u" {
+			t.Errorf("unexpected proxy params %v", q)
+		}
+	}
+}
+
+func TestConnectViaProxyRejectsInvalidConfig(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	_, err := client.Connection.ConnectViaProxy(ConnectParams{User: 1001}, ProxyConfig{Type: "ftp", Host: "proxy", Port: 21})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument, got %v", err)
+	}
+
+	_, err = client.Connection.ConnectWith(CredentialFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{User: 1001, Password: "p", Server: "Demo", Proxy: &ProxyConfig{Type: ProxyHTTP, Host: "proxy", Port: 8080}}, nil
+	}))
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument for proxy without host, got %v", err)
+	}
+	if called {
+		t.Error("expected no request for invalid proxy config")
+	}
+}
@@ -1,6 +1,9 @@
 package mt5client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // QuoteService จัดการราคา
 type QuoteService struct {
@@ -9,17 +12,26 @@ type QuoteService struct {
 
 // Get ดึงราคาของสัญลักษณ์
 func (r *QuoteService) Get(symbol string) (*Quote, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetCtx(context.Background(), symbol)
+}
+
+// GetCtx ดึงราคาของสัญลักษณ์ (รองรับ context)
+func (r *QuoteService) GetCtx(ctx context.Context, symbol string) (*Quote, error) {
+	ctx, span := r.client.startSpan(ctx, "QuoteService.Get", Attr("symbol", symbol))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 	}
 
 	var quote Quote
-	err := r.client.get("/GetQuote", queryParams, &quote)
+	err := r.client.get(ctx, "/GetQuote", queryParams, &quote)
 	if err != nil {
 		return nil, err
 	}
@@ -29,12 +41,21 @@ func (r *QuoteService) Get(symbol string) (*Quote, error) {
 
 // GetMany ดึงราคาหลายสัญลักษณ์
 func (r *QuoteService) GetMany(symbols []string) ([]Quote, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetManyCtx(context.Background(), symbols)
+}
+
+// GetManyCtx ดึงราคาหลายสัญลักษณ์ (รองรับ context)
+func (r *QuoteService) GetManyCtx(ctx context.Context, symbols []string) ([]Quote, error) {
+	ctx, span := r.client.startSpan(ctx, "QuoteService.GetMany", Attr("symbols", symbols))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	// เพิ่ม symbols เป็น array
@@ -43,7 +64,7 @@ func (r *QuoteService) GetMany(symbols []string) ([]Quote, error) {
 	}
 
 	var quotes []Quote
-	err := r.client.get("/GetQuoteMany", queryParams, &quotes)
+	err := r.client.get(ctx, "/GetQuoteMany", queryParams, &quotes)
 	if err != nil {
 		return nil, err
 	}
@@ -53,12 +74,21 @@ func (r *QuoteService) GetMany(symbols []string) ([]Quote, error) {
 
 // GetTickValueMany ดึง tick value หลายสัญลักษณ์
 func (r *QuoteService) GetTickValueMany(symbols []string) (map[string]float64, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetTickValueManyCtx(context.Background(), symbols)
+}
+
+// GetTickValueManyCtx ดึง tick value หลายสัญลักษณ์ (รองรับ context)
+func (r *QuoteService) GetTickValueManyCtx(ctx context.Context, symbols []string) (map[string]float64, error) {
+	ctx, span := r.client.startSpan(ctx, "QuoteService.GetTickValueMany", Attr("symbols", symbols))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	for i, symbol := range symbols {
@@ -66,7 +96,7 @@ func (r *QuoteService) GetTickValueMany(symbols []string) (map[string]float64, e
 	}
 
 	var tickValues map[string]float64
-	err := r.client.get("/GetTickValueMany", queryParams, &tickValues)
+	err := r.client.get(ctx, "/GetTickValueMany", queryParams, &tickValues)
 	if err != nil {
 		return nil, err
 	}
@@ -76,18 +106,27 @@ func (r *QuoteService) GetTickValueMany(symbols []string) (map[string]float64, e
 
 // GetTickValueWithSize ดึง tick value พร้อมขนาด
 func (r *QuoteService) GetTickValueWithSize(symbol string, volume float64) (float64, error) {
-	if r.client.token == "" {
-		return 0, fmt.Errorf("not connected")
+	return r.GetTickValueWithSizeCtx(context.Background(), symbol, volume)
+}
+
+// GetTickValueWithSizeCtx ดึง tick value พร้อมขนาด (รองรับ context)
+func (r *QuoteService) GetTickValueWithSizeCtx(ctx context.Context, symbol string, volume float64) (float64, error) {
+	ctx, span := r.client.startSpan(ctx, "QuoteService.GetTickValueWithSize", Attr("symbol", symbol), Attr("volume", volume))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return 0, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 		"volume": fmt.Sprintf("%.2f", volume),
 	}
 
 	var tickValue float64
-	err := r.client.get("/TickValueWithSize", queryParams, &tickValue)
+	err := r.client.get(ctx, "/TickValueWithSize", queryParams, &tickValue)
 	if err != nil {
 		return 0, err
 	}
@@ -97,17 +136,26 @@ func (r *QuoteService) GetTickValueWithSize(symbol string, volume float64) (floa
 
 // IsQuoteSession ตรวจสอบว่าอยู่ในเซสชันราคาหรือไม่
 func (r *QuoteService) IsQuoteSession(symbol string) (bool, error) {
-	if r.client.token == "" {
-		return false, fmt.Errorf("not connected")
+	return r.IsQuoteSessionCtx(context.Background(), symbol)
+}
+
+// IsQuoteSessionCtx ตรวจสอบว่าอยู่ในเซสชันราคาหรือไม่ (รองรับ context)
+func (r *QuoteService) IsQuoteSessionCtx(ctx context.Context, symbol string) (bool, error) {
+	ctx, span := r.client.startSpan(ctx, "QuoteService.IsQuoteSession", Attr("symbol", symbol))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return false, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 	}
 
 	var result bool
-	err := r.client.get("/IsQuoteSession", queryParams, &result)
+	err := r.client.get(ctx, "/IsQuoteSession", queryParams, &result)
 	if err != nil {
 		return false, err
 	}
@@ -117,12 +165,21 @@ func (r *QuoteService) IsQuoteSession(symbol string) (bool, error) {
 
 // IsQuoteSessionMany ตรวจสอบหลายสัญลักษณ์
 func (r *QuoteService) IsQuoteSessionMany(symbols []string) (map[string]bool, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.IsQuoteSessionManyCtx(context.Background(), symbols)
+}
+
+// IsQuoteSessionManyCtx ตรวจสอบหลายสัญลักษณ์ (รองรับ context)
+func (r *QuoteService) IsQuoteSessionManyCtx(ctx context.Context, symbols []string) (map[string]bool, error) {
+	ctx, span := r.client.startSpan(ctx, "QuoteService.IsQuoteSessionMany", Attr("symbols", symbols))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	for i, symbol := range symbols {
@@ -130,7 +187,7 @@ func (r *QuoteService) IsQuoteSessionMany(symbols []string) (map[string]bool, er
 	}
 
 	var results map[string]bool
-	err := r.client.get("/IsQuoteSessionMany", queryParams, &results)
+	err := r.client.get(ctx, "/IsQuoteSessionMany", queryParams, &results)
 	if err != nil {
 		return nil, err
 	}
@@ -140,17 +197,26 @@ func (r *QuoteService) IsQuoteSessionMany(symbols []string) (map[string]bool, er
 
 // IsTradeSession ตรวจสอบว่าอยู่ในเซสชันเทรดหรือไม่
 func (r *QuoteService) IsTradeSession(symbol string) (bool, error) {
-	if r.client.token == "" {
-		return false, fmt.Errorf("not connected")
+	return r.IsTradeSessionCtx(context.Background(), symbol)
+}
+
+// IsTradeSessionCtx ตรวจสอบว่าอยู่ในเซสชันเทรดหรือไม่ (รองรับ context)
+func (r *QuoteService) IsTradeSessionCtx(ctx context.Context, symbol string) (bool, error) {
+	ctx, span := r.client.startSpan(ctx, "QuoteService.IsTradeSession", Attr("symbol", symbol))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return false, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 	}
 
 	var result bool
-	err := r.client.get("/IsTradeSession", queryParams, &result)
+	err := r.client.get(ctx, "/IsTradeSession", queryParams, &result)
 	if err != nil {
 		return false, err
 	}
@@ -160,12 +226,21 @@ func (r *QuoteService) IsTradeSession(symbol string) (bool, error) {
 
 // IsTradeSessionMany ตรวจสอบหลายสัญลักษณ์
 func (r *QuoteService) IsTradeSessionMany(symbols []string) (map[string]bool, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.IsTradeSessionManyCtx(context.Background(), symbols)
+}
+
+// IsTradeSessionManyCtx ตรวจสอบหลายสัญลักษณ์ (รองรับ context)
+func (r *QuoteService) IsTradeSessionManyCtx(ctx context.Context, symbols []string) (map[string]bool, error) {
+	ctx, span := r.client.startSpan(ctx, "QuoteService.IsTradeSessionMany", Attr("symbols", symbols))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	for i, symbol := range symbols {
@@ -173,7 +248,7 @@ func (r *QuoteService) IsTradeSessionMany(symbols []string) (map[string]bool, er
 	}
 
 	var results map[string]bool
-	err := r.client.get("/IsTradeSessionMany", queryParams, &results)
+	err := r.client.get(ctx, "/IsTradeSessionMany", queryParams, &results)
 	if err != nil {
 		return nil, err
 	}
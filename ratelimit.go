@@ -0,0 +1,211 @@
+package mt5client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointGroup กลุ่มของ endpoint สำหรับจำกัดอัตราแยกกัน
+type EndpointGroup string
+
+const (
+	EndpointGroupDefault    EndpointGroup = "default"     // endpoint ทั่วไป (บัญชี, ประวัติ, บริการ)
+	EndpointGroupTrading    EndpointGroup = "trading"     // ส่ง/แก้ไข/ปิดคำสั่ง
+	EndpointGroupMarketData EndpointGroup = "market_data" // ราคา, สัญลักษณ์, subscription
+)
+
+// marketDataPrefixes prefix ของ endpoint กลุ่ม market data
+var marketDataPrefixes = []string{
+	"/GetQuote",
+	"/GetTickValue",
+	"/TickValue",
+	"/PriceHistory",
+	"/TickHistory",
+	"/Symbol",
+	"/Symbols",
+	"/SubscribedSymbols",
+	"/IsQuoteSession",
+	"/IsTradeSession",
+	"/MarketWatch",
+	"/Subscribe",
+	"/UnSubscribe",
+	"/Unsubscribe",
+}
+
+// endpointGroup หา group ของ endpoint
+func endpointGroup(endpoint string) EndpointGroup {
+	switch endpoint {
+	case "/OrderSend", "/OrderModify", "/OrderClose":
+		return EndpointGroupTrading
+	}
+
+	for _, prefix := range marketDataPrefixes {
+		if strings.HasPrefix(endpoint, prefix) {
+			return EndpointGroupMarketData
+		}
+	}
+
+	return EndpointGroupDefault
+}
+
+// WithRateLimit จำกัดจำนวน request ต่อวินาทีของทั้ง Client (token bucket)
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.limiter.bucket = newTokenBucket(requestsPerSecond, burst)
+	}
+}
+
+// WithMaxInFlight จำกัดจำนวน request ที่ส่งพร้อมกันของทั้ง Client
+func WithMaxInFlight(n int) Option {
+	return func(c *Client) {
+		c.limiter.sem = newSemaphore(n)
+	}
+}
+
+// WithGroupRateLimit จำกัดจำนวน request ต่อวินาทีเฉพาะ group (ใช้ร่วมกับ limit ของทั้ง Client)
+func WithGroupRateLimit(group EndpointGroup, requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.groupLimiter(group).bucket = newTokenBucket(requestsPerSecond, burst)
+	}
+}
+
+// WithGroupMaxInFlight จำกัดจำนวน request ที่ส่งพร้อมกันเฉพาะ group
+func WithGroupMaxInFlight(group EndpointGroup, n int) Option {
+	return func(c *Client) {
+		c.groupLimiter(group).sem = newSemaphore(n)
+	}
+}
+
+// groupLimiter ดึง (หรือสร้าง) limiter ของ group
+func (r *Client) groupLimiter(group EndpointGroup) *requestLimiter {
+	l, ok := r.groupLimiters[group]
+	if !ok {
+		l = &requestLimiter{}
+		r.groupLimiters[group] = l
+	}
+	return l
+}
+
+// limitWaitError รอ token/slot ของ rate limiter ไม่สำเร็จ (request ยังไม่ถูกส่ง)
+type limitWaitError struct {
+	err error
+}
+
+func (e *limitWaitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *limitWaitError) Unwrap() error {
+	return e.err
+}
+
+// acquire รอจนกว่าจะส่ง request ไปยัง endpoint ได้ คืน release func สำหรับคืน slot
+func (r *Client) acquire(ctx context.Context, endpoint string) (func(), error) {
+	releaseClient, err := r.limiter.acquire(ctx)
+	if err != nil {
+		return nil, &limitWaitError{err: err}
+	}
+
+	l, ok := r.groupLimiters[endpointGroup(endpoint)]
+	if !ok {
+		return releaseClient, nil
+	}
+
+	releaseGroup, err := l.acquire(ctx)
+	if err != nil {
+		releaseClient()
+		return nil, &limitWaitError{err: err}
+	}
+
+	return func() {
+		releaseGroup()
+		releaseClient()
+	}, nil
+}
+
+// requestLimiter รวม token bucket และ semaphore
+type requestLimiter struct {
+	bucket *tokenBucket
+	sem    chan struct{}
+}
+
+// acquire รอ token และ slot
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l.bucket != nil {
+		if err := l.bucket.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.sem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.sem <- struct{}{}:
+		return func() { <-l.sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// newSemaphore สร้าง semaphore (n <= 0 = ไม่จำกัด)
+func newSemaphore(n int) chan struct{} {
+	if n <= 0 {
+		return nil
+	}
+	return make(chan struct{}, n)
+}
+
+// tokenBucket rate limiter แบบ token bucket
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens ต่อวินาที
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket สร้าง token bucket (rate <= 0 = ไม่จำกัด)
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait รอจนกว่าจะได้ token หรือ ctx ถูก cancel
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package mt5client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEndpointGroup(t *testing.T) {
+	tests := map[string]EndpointGroup{
+		"/OrderSend":      EndpointGroupTrading,
+		"/OrderClose":     EndpointGroupTrading,
+		"/GetQuote":       EndpointGroupMarketData,
+		"/GetQuoteMany":   EndpointGroupMarketData,
+		"/SymbolParams":   EndpointGroupMarketData,
+		"/PriceHistoryEx": EndpointGroupMarketData,
+		"/Account":        EndpointGroupDefault,
+		"/OpenedOrders":   EndpointGroupDefault,
+	}
+
+	for endpoint, want := range tests {
+		if got := endpointGroup(endpoint); got != want {
+			t.Errorf("endpointGroup(%s) = %s, want %s", endpoint, got, want)
+		}
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		w.Write([]byte(`{"symbol":"EURUSD"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithGroupMaxInFlight(EndpointGroupMarketData, 2))
+	client.SetToken("token")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Quote.Get("EURUSD"); err != nil {
+				t.Errorf("Get failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&peak); got > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", got)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"OK"`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRateLimit(50, 1))
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.Service.Ping(); err != nil {
+			t.Fatalf("Ping failed: %v", err)
+		}
+	}
+
+	// token แรกได้ทันที อีก 4 ครั้งต้องรอครั้งละ ~20ms
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("expected rate limited calls to take >= 70ms, took %v", elapsed)
+	}
+}
@@ -0,0 +1,71 @@
+package mt5client
+
+import (
+	"errors"
+	"net/url"
+)
+
+// redactedValue ค่าที่ใช้แทนข้อมูลลับ
+const redactedValue = "REDACTED"
+
+// secretParams query parameter ที่เป็นข้อมูลลับ (token และรหัสผ่าน)
+var secretParams = map[string]bool{
+	"id":            true,
+	"password":      true,
+	"oldPassword":   true,
+	"newPassword":   true,
+	"proxyPassword": true,
+}
+
+// IsSecretParam ตรวจสอบว่า query parameter นี้เป็นข้อมูลลับหรือไม่
+func IsSecretParam(key string) bool {
+	return secretParams[key]
+}
+
+// RedactParams คัดลอก params โดยแทนค่าลับด้วย "REDACTED" (ใช้ก่อน log หรือบันทึก)
+func RedactParams(params map[string]string) map[string]string {
+	if params == nil {
+		return nil
+	}
+
+	out := make(map[string]string, len(params))
+	for k, v := range params {
+		if secretParams[k] {
+			v = redactedValue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// redactURL ซ่อน token และรหัสผ่านใน URL ก่อนนำไป log หรือใส่ใน error
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		// แยกส่วนไม่ได้ จึงซ่อนทั้ง URL
+		return redactedValue
+	}
+
+	query := u.Query()
+	changed := false
+	for key := range query {
+		if secretParams[key] {
+			query.Set(key, redactedValue)
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String()
+}
+
+// redactError ซ่อนข้อมูลลับใน URL ของ *url.Error (error จาก http.Client)
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(urlErr.URL)
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package mt5client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactURL(t *testing.T) {
+	got := redactURL("ws://localhost:5000/Connect?user=1&password=hunter2&id=secret-token")
+	if strings.Contains(got, "secret-token") || strings.Contains(got, "hunter2") {
+		t.Errorf("secrets not redacted: %s", got)
+	}
+	if !strings.Contains(got, "user=1") {
+		t.Errorf("non-secret params removed: %s", got)
+	}
+
+	if got := redactURL("http://[bad host/Connect?password=hunter2"); got != "REDACTED" {
+		t.Errorf("expected unparsable URL to be fully redacted, got %s", got)
+	}
+}
+
+func TestRedactParams(t *testing.T) {
+	params := map[string]string{"id": "token", "oldPassword": "a", "newPassword": "b", "symbol": "EURUSD"}
+	got := RedactParams(params)
+
+	for _, key := range []string{"id", "oldPassword", "newPassword"} {
+		if got[key] != "REDACTED" {
+			t.Errorf("%s not redacted: %s", key, got[key])
+		}
+	}
+	if got["symbol"] != "EURUSD" {
+		t.Errorf("symbol changed: %s", got["symbol"])
+	}
+	if params["id"] != "token" {
+		t.Error("original params were modified")
+	}
+}
+
+func TestTransportErrorIsRedacted(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	client := NewClient(url, WithLogger(nil))
+	_, err := client.Connection.Connect(ConnectParams{User: 1, Password: "hunter2"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("password leaked into error: %v", err)
+	}
+}
+
+func TestWithTokenHeader(t *testing.T) {
+	var gotHeader, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Session")
+		gotQuery = r.URL.Query().Get("id")
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithTokenHeader("X-Session"))
+	client.SetToken("secret-token")
+
+	if _, err := client.Connection.IsConnected(); err != nil {
+		t.Fatalf("IsConnected failed: %v", err)
+	}
+	if gotHeader != "secret-token" {
+		t.Errorf("expected token in header, got %q", gotHeader)
+	}
+	if gotQuery != "" {
+		t.Errorf("expected no id query parameter, got %q", gotQuery)
+	}
+}
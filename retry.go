@@ -0,0 +1,146 @@
+package mt5client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy นโยบายการ retry สำหรับ endpoint ที่เป็น idempotent (อ่านข้อมูล)
+type RetryPolicy struct {
+	MaxAttempts          int           // จำนวนครั้งสูงสุดรวมครั้งแรก (1 = ไม่ retry)
+	InitialBackoff       time.Duration // ระยะรอก่อน retry ครั้งแรก
+	MaxBackoff           time.Duration // ระยะรอสูงสุด
+	Multiplier           float64       // ตัวคูณ backoff ต่อครั้ง
+	Jitter               float64       // สัดส่วนการสุ่มลดระยะรอ (0-1)
+	RetryableStatusCodes []int         // HTTP status ที่ควร retry
+}
+
+// DefaultRetryPolicy นโยบาย retry เริ่มต้น
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// NoRetryPolicy ปิดการ retry
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// WithRetryPolicy กำหนดนโยบาย retry
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// nonIdempotentEndpoints endpoint ที่ห้าม retry อัตโนมัติ (เรียกซ้ำแล้วอาจเกิดผลซ้ำ)
+var nonIdempotentEndpoints = map[string]bool{
+	"/OrderSend":      true,
+	"/OrderClose":     true,
+	"/OrderModify":    true,
+	"/Connect":        true,
+	"/ConnectEx":      true,
+	"/ConnectProxy":   true,
+	"/Disconnect":     true,
+	"/ChangePassword": true,
+	"/GetDemo":        true,
+	"/LoadServersDat": true,
+}
+
+type idempotencyKey struct{}
+
+// ContextWithIdempotency ระบุว่าคำขอนี้มีกลไกป้องกันการทำซ้ำแล้ว
+// ทำให้ endpoint ที่ไม่ idempotent (เช่น /OrderSend) retry ได้
+func ContextWithIdempotency(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, true)
+}
+
+// isRetryableEndpoint ตรวจสอบว่า endpoint นี้ retry ได้หรือไม่
+func isRetryableEndpoint(ctx context.Context, endpoint string) bool {
+	if !nonIdempotentEndpoints[endpoint] {
+		return true
+	}
+	guarded, _ := ctx.Value(idempotencyKey{}).(bool)
+	return guarded
+}
+
+// shouldRetry ตรวจสอบว่า error นี้ควร retry หรือไม่
+func (p RetryPolicy) shouldRetry(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		for _, code := range p.RetryableStatusCodes {
+			if apiErr.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	// error ระดับ transport (connection reset, timeout ฯลฯ)
+	var sendErr *sendError
+	return errors.As(err, &sendErr)
+}
+
+// backoff คำนวณระยะรอก่อน retry ครั้งที่ attempt (เริ่มที่ 1)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay -= delay * p.Jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// sleepContext รอตามเวลาที่กำหนด หรือจนกว่า ctx จะถูก cancel
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sendError error ระหว่างส่ง request (ระดับ network)
+type sendError struct {
+	err error
+}
+
+func (e *sendError) Error() string {
+	return "failed to send request: " + e.err.Error()
+}
+
+func (e *sendError) Unwrap() error {
+	return e.err
+}
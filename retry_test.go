@@ -0,0 +1,63 @@
+package mt5client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func TestRetryIdempotentEndpoint(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"symbol":"EURUSD","bid":1.1,"ask":1.2}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	client.SetToken("token")
+
+	quote, err := client.Quote.Get("EURUSD")
+	if err != nil {
+		t.Fatalf("expected success after retry, got %v", err)
+	}
+	if quote.Symbol != "EURUSD" {
+		t.Errorf("expected EURUSD, got %s", quote.Symbol)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("expected 3 calls, got %d", got)
+	}
+}
+
+func TestNoRetryForOrderSend(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetryPolicy(testRetryPolicy()))
+	client.SetToken("token")
+
+	_, err := client.Trading.Buy("EURUSD", 0.01, 0, 0)
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("expected ErrServer, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("expected 1 call for /OrderSend, got %d", got)
+	}
+}
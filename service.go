@@ -1,6 +1,9 @@
 package mt5client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // ServiceFunctions ฟังก์ชันบริการทั่วไป
 type ServiceFunctions struct {
@@ -9,31 +12,63 @@ type ServiceFunctions struct {
 
 // GetVersion ดึงเวอร์ชันของ API
 func (r *ServiceFunctions) GetVersion() (string, error) {
+	return r.GetVersionCtx(context.Background())
+}
+
+// GetVersionCtx ดึงเวอร์ชันของ API (รองรับ context)
+func (r *ServiceFunctions) GetVersionCtx(ctx context.Context) (string, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.GetVersion")
+	defer span.End()
+
 	var version string
-	err := r.client.get("/Version", nil, &version)
+	err := r.client.get(ctx, "/Version", nil, &version)
 	return version, err
 }
 
 // Ping ทดสอบการเชื่อมต่อ
 func (r *ServiceFunctions) Ping() (string, error) {
+	return r.PingCtx(context.Background())
+}
+
+// PingCtx ทดสอบการเชื่อมต่อ (รองรับ context)
+func (r *ServiceFunctions) PingCtx(ctx context.Context) (string, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.Ping")
+	defer span.End()
+
 	var result string
-	err := r.client.get("/Ping", nil, &result)
+	err := r.client.get(ctx, "/Ping", nil, &result)
 	return result, err
 }
 
 // PingHost ทดสอบการเชื่อมต่อไปยัง host
 func (r *ServiceFunctions) PingHost(host string) (bool, error) {
+	return r.PingHostCtx(context.Background(), host)
+}
+
+// PingHostCtx ทดสอบการเชื่อมต่อไปยัง host (รองรับ context)
+func (r *ServiceFunctions) PingHostCtx(ctx context.Context, host string) (bool, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.PingHost", Attr("host", host))
+	defer span.End()
+
 	queryParams := map[string]string{
 		"host": host,
 	}
 
 	var result bool
-	err := r.client.get("/PingHost", queryParams, &result)
+	err := r.client.get(ctx, "/PingHost", queryParams, &result)
 	return result, err
 }
 
 // PingHostMany ทดสอบหลาย hosts
 func (r *ServiceFunctions) PingHostMany(hosts []string) (map[string]bool, error) {
+	return r.PingHostManyCtx(context.Background(), hosts)
+}
+
+// PingHostManyCtx ทดสอบหลาย hosts (รองรับ context)
+func (r *ServiceFunctions) PingHostManyCtx(ctx context.Context, hosts []string) (map[string]bool, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.PingHostMany", Attr("hosts", hosts))
+	defer span.End()
+
 	queryParams := map[string]string{}
 
 	for i, host := range hosts {
@@ -41,75 +76,126 @@ func (r *ServiceFunctions) PingHostMany(hosts []string) (map[string]bool, error)
 	}
 
 	var results map[string]bool
-	err := r.client.get("/PingHostMany", queryParams, &results)
+	err := r.client.get(ctx, "/PingHostMany", queryParams, &results)
 	return results, err
 }
 
 // Search ค้นหาสัญลักษณ์
 func (r *ServiceFunctions) Search(keyword string) ([]string, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.SearchCtx(context.Background(), keyword)
+}
+
+// SearchCtx ค้นหาสัญลักษณ์ (รองรับ context)
+func (r *ServiceFunctions) SearchCtx(ctx context.Context, keyword string) ([]string, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.Search", Attr("keyword", keyword))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":      r.client.token,
+		"id":      token,
 		"keyword": keyword,
 	}
 
 	var results []string
-	err := r.client.get("/Search", queryParams, &results)
+	err := r.client.get(ctx, "/Search", queryParams, &results)
 	return results, err
 }
 
 // GetServerTimezone ดึงข้อมูล timezone ของ server
 func (r *ServiceFunctions) GetServerTimezone() (string, error) {
-	if r.client.token == "" {
-		return "", fmt.Errorf("not connected")
+	return r.GetServerTimezoneCtx(context.Background())
+}
+
+// GetServerTimezoneCtx ดึงข้อมูล timezone ของ server (รองรับ context)
+func (r *ServiceFunctions) GetServerTimezoneCtx(ctx context.Context) (string, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.GetServerTimezone")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return "", ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var timezone string
-	err := r.client.get("/ServerTimezone", queryParams, &timezone)
+	err := r.client.get(ctx, "/ServerTimezone", queryParams, &timezone)
 	return timezone, err
 }
 
 // GetClusterDetails ดึงข้อมูล cluster
 func (r *ServiceFunctions) GetClusterDetails() (map[string]interface{}, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetClusterDetailsCtx(context.Background())
+}
+
+// GetClusterDetailsCtx ดึงข้อมูล cluster (รองรับ context)
+func (r *ServiceFunctions) GetClusterDetailsCtx(ctx context.Context) (map[string]interface{}, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.GetClusterDetails")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var details map[string]interface{}
-	err := r.client.get("/ClusterDetails", queryParams, &details)
+	err := r.client.get(ctx, "/ClusterDetails", queryParams, &details)
 	return details, err
 }
 
-// ChangePassword เปลี่ยนรหัสผ่าน
+// ChangePassword เปลี่ยนรหัสผ่าน (Session ที่ผูกอยู่จะ re-login ด้วยรหัสผ่านใหม่)
 func (r *ServiceFunctions) ChangePassword(oldPassword, newPassword string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.ChangePasswordCtx(context.Background(), oldPassword, newPassword)
+}
+
+// ChangePasswordCtx เปลี่ยนรหัสผ่าน (รองรับ context)
+func (r *ServiceFunctions) ChangePasswordCtx(ctx context.Context, oldPassword, newPassword string) error {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.ChangePassword")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":          r.client.token,
+		"id":          token,
 		"oldPassword": oldPassword,
 		"newPassword": newPassword,
 	}
 
 	var result string
-	err := r.client.get("/ChangePassword", queryParams, &result)
-	return err
+	if err := r.client.sendCredentials(ctx, "/ChangePassword", queryParams, &result); err != nil {
+		return err
+	}
+
+	// ให้ Session re-login ด้วยรหัสผ่านใหม่ (ถ้าเชื่อมต่อด้วย CredentialProvider ผู้ใช้ต้องอัปเดต provider เอง)
+	if session := r.client.getSession(); session != nil {
+		session.passwordChanged(oldPassword, newPassword)
+	}
+	return nil
 }
 
 // GetDemo ขอบัญชี demo
 func (r *ServiceFunctions) GetDemo(server, name, email string) (map[string]interface{}, error) {
+	return r.GetDemoCtx(context.Background(), server, name, email)
+}
+
+// GetDemoCtx ขอบัญชี demo (รองรับ context)
+func (r *ServiceFunctions) GetDemoCtx(ctx context.Context, server, name, email string) (map[string]interface{}, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.GetDemo", Attr("server", server))
+	defer span.End()
+
 	queryParams := map[string]string{
 		"server": server,
 		"name":   name,
@@ -117,50 +203,77 @@ func (r *ServiceFunctions) GetDemo(server, name, email string) (map[string]inter
 	}
 
 	var result map[string]interface{}
-	err := r.client.get("/GetDemo", queryParams, &result)
+	err := r.client.get(ctx, "/GetDemo", queryParams, &result)
 	return result, err
 }
 
 // GetRequiredMargin คำนวณ margin ที่ต้องการ
 func (r *ServiceFunctions) GetRequiredMargin(symbol string, volume float64) (float64, error) {
-	if r.client.token == "" {
-		return 0, fmt.Errorf("not connected")
+	return r.GetRequiredMarginCtx(context.Background(), symbol, volume)
+}
+
+// GetRequiredMarginCtx คำนวณ margin ที่ต้องการ (รองรับ context)
+func (r *ServiceFunctions) GetRequiredMarginCtx(ctx context.Context, symbol string, volume float64) (float64, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.GetRequiredMargin", Attr("symbol", symbol), Attr("volume", volume))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return 0, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 		"volume": fmt.Sprintf("%.2f", volume),
 	}
 
 	var margin float64
-	err := r.client.get("/RequiredMargin", queryParams, &margin)
+	err := r.client.get(ctx, "/RequiredMargin", queryParams, &margin)
 	return margin, err
 }
 
 // GetMails ดึงอีเมล
 func (r *ServiceFunctions) GetMails() ([]Mail, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetMailsCtx(context.Background())
+}
+
+// GetMailsCtx ดึงอีเมล (รองรับ context)
+func (r *ServiceFunctions) GetMailsCtx(ctx context.Context) ([]Mail, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.GetMails")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var mails []Mail
-	err := r.client.get("/Mails", queryParams, &mails)
+	err := r.client.get(ctx, "/Mails", queryParams, &mails)
 	return mails, err
 }
 
 // GetMarketWatchMany ดึง market watch หลายสัญลักษณ์
 func (r *ServiceFunctions) GetMarketWatchMany(symbols []string) ([]MarketWatch, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetMarketWatchManyCtx(context.Background(), symbols)
+}
+
+// GetMarketWatchManyCtx ดึง market watch หลายสัญลักษณ์ (รองรับ context)
+func (r *ServiceFunctions) GetMarketWatchManyCtx(ctx context.Context, symbols []string) ([]MarketWatch, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.GetMarketWatchMany", Attr("symbols", symbols))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	for i, symbol := range symbols {
@@ -168,51 +281,85 @@ func (r *ServiceFunctions) GetMarketWatchMany(symbols []string) ([]MarketWatch,
 	}
 
 	var marketWatch []MarketWatch
-	err := r.client.get("/MarketWatchMany", queryParams, &marketWatch)
+	err := r.client.get(ctx, "/MarketWatchMany", queryParams, &marketWatch)
 	return marketWatch, err
 }
 
 // GetQuoteClient ดึงข้อมูล quote client
 func (r *ServiceFunctions) GetQuoteClient() (map[string]interface{}, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetQuoteClientCtx(context.Background())
+}
+
+// GetQuoteClientCtx ดึงข้อมูล quote client (รองรับ context)
+func (r *ServiceFunctions) GetQuoteClientCtx(ctx context.Context) (map[string]interface{}, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.GetQuoteClient")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var client map[string]interface{}
-	err := r.client.get("/QuoteClient", queryParams, &client)
+	err := r.client.get(ctx, "/QuoteClient", queryParams, &client)
 	return client, err
 }
 
 // LoadServersDat โหลดไฟล์ servers.dat
 func (r *ServiceFunctions) LoadServersDat(data []byte) error {
+	return r.LoadServersDatCtx(context.Background(), data)
+}
+
+// LoadServersDatCtx โหลดไฟล์ servers.dat (รองรับ context)
+func (r *ServiceFunctions) LoadServersDatCtx(ctx context.Context, data []byte) error {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.LoadServersDat")
+	defer span.End()
+
 	var result string
-	err := r.client.post("/LoadServersDat", nil, data, &result)
+	err := r.client.post(ctx, "/LoadServersDat", nil, data, &result)
 	return err
 }
 
 // GetMetricsApiKey ดึง API key สำหรับ metrics
 func (r *ServiceFunctions) GetMetricsApiKey() (string, error) {
-	if r.client.token == "" {
-		return "", fmt.Errorf("not connected")
+	return r.GetMetricsApiKeyCtx(context.Background())
+}
+
+// GetMetricsApiKeyCtx ดึง API key สำหรับ metrics (รองรับ context)
+func (r *ServiceFunctions) GetMetricsApiKeyCtx(ctx context.Context) (string, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.GetMetricsApiKey")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return "", ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var apiKey string
-	err := r.client.get("/MetricsApiKey", queryParams, &apiKey)
+	err := r.client.get(ctx, "/MetricsApiKey", queryParams, &apiKey)
 	return apiKey, err
 }
 
 // GetReadMe ดึง README
 func (r *ServiceFunctions) GetReadMe() (string, error) {
+	return r.GetReadMeCtx(context.Background())
+}
+
+// GetReadMeCtx ดึง README (รองรับ context)
+func (r *ServiceFunctions) GetReadMeCtx(ctx context.Context) (string, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.GetReadMe")
+	defer span.End()
+
 	var readme string
-	err := r.client.get("/ReadMe", nil, &readme)
+	err := r.client.get(ctx, "/ReadMe", nil, &readme)
 	return readme, err
 }
 
@@ -239,21 +386,31 @@ type LotSizeResult struct {
 // CalculateLotSize คำนวณ lot size จาก risk amount (เงิน)
 // สูตร: Lot Size = Risk Amount / (Point Distance × Tick Value)
 func (r *ServiceFunctions) CalculateLotSize(symbol string, entryPrice, stopLoss, riskAmount float64) (*LotSizeResult, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.CalculateLotSizeCtx(context.Background(), symbol, entryPrice, stopLoss, riskAmount)
+}
+
+// CalculateLotSizeCtx คำนวณ lot size จาก risk amount (เงิน) (รองรับ context)
+// สูตร: Lot Size = Risk Amount / (Point Distance × Tick Value)
+func (r *ServiceFunctions) CalculateLotSizeCtx(ctx context.Context, symbol string, entryPrice, stopLoss, riskAmount float64) (*LotSizeResult, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.CalculateLotSize", Attr("symbol", symbol), Attr("riskAmount", riskAmount))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	if stopLoss <= 0 {
-		return nil, fmt.Errorf("stop loss must be greater than 0")
+		return nil, fmt.Errorf("%w: stop loss must be greater than 0", ErrInvalidArgument)
 	}
 
 	if riskAmount <= 0 {
-		return nil, fmt.Errorf("risk amount must be greater than 0")
+		return nil, fmt.Errorf("%w: risk amount must be greater than 0", ErrInvalidArgument)
 	}
 
 	// ถ้า entryPrice = 0 ให้ใช้ราคา market ปัจจุบัน
 	if entryPrice == 0 {
-		quote, err := r.client.Quote.Get(symbol)
+		quote, err := r.client.Quote.GetCtx(ctx, symbol)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get current price: %w", err)
 		}
@@ -270,14 +427,14 @@ func (r *ServiceFunctions) CalculateLotSize(symbol string, entryPrice, stopLoss,
 			entryPrice = quote.Bid
 		} else {
 			// SL = midPrice (ไม่น่าเกิด แต่ป้องกันไว้)
-			return nil, fmt.Errorf("stop loss cannot be equal to current market price")
+			return nil, fmt.Errorf("%w: stop loss cannot be equal to current market price", ErrInvalidArgument)
 		}
 	} else if entryPrice < 0 {
-		return nil, fmt.Errorf("entry price must be greater than or equal to 0 (use 0 for market price)")
+		return nil, fmt.Errorf("%w: entry price must be greater than or equal to 0 (use 0 for market price)", ErrInvalidArgument)
 	}
 
 	// ดึงข้อมูล Symbol เพื่อได้ Point value
-	symbolParams, err := r.client.Symbol.GetParams(symbol)
+	symbolParams, err := r.client.Symbol.GetParamsCtx(ctx, symbol)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get symbol info: %w", err)
 	}
@@ -289,7 +446,7 @@ func (r *ServiceFunctions) CalculateLotSize(symbol string, entryPrice, stopLoss,
 	// คำนวณ Tick Value ถ้า API ส่งมาเป็น 0
 	tickValue := symbolParams.SymbolInfo.TickValue
 	if tickValue <= 0 {
-		tickValue, err = r.calculateTickValue(&symbolParams.SymbolInfo, symbol)
+		tickValue, err = r.calculateTickValue(ctx, &symbolParams.SymbolInfo, symbol)
 		if err != nil {
 			return nil, fmt.Errorf("failed to calculate tick value: %w", err)
 		}
@@ -332,16 +489,25 @@ func (r *ServiceFunctions) CalculateLotSize(symbol string, entryPrice, stopLoss,
 
 // CalculateLotSizeByPercent คำนวณ lot size จาก risk % ของพอร์ต
 func (r *ServiceFunctions) CalculateLotSizeByPercent(symbol string, entryPrice, stopLoss, riskPercent float64) (*LotSizeResult, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.CalculateLotSizeByPercentCtx(context.Background(), symbol, entryPrice, stopLoss, riskPercent)
+}
+
+// CalculateLotSizeByPercentCtx คำนวณ lot size จาก risk % ของพอร์ต (รองรับ context)
+func (r *ServiceFunctions) CalculateLotSizeByPercentCtx(ctx context.Context, symbol string, entryPrice, stopLoss, riskPercent float64) (*LotSizeResult, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.CalculateLotSizeByPercent", Attr("symbol", symbol), Attr("riskPercent", riskPercent))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	if riskPercent <= 0 || riskPercent > 100 {
-		return nil, fmt.Errorf("risk percent must be between 0 and 100")
+		return nil, fmt.Errorf("%w: risk percent must be between 0 and 100", ErrInvalidArgument)
 	}
 
 	// ดึงข้อมูลบัญชีเพื่อได้ Balance
-	account, err := r.client.Account.GetInfo()
+	account, err := r.client.Account.GetInfoCtx(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account info: %w", err)
 	}
@@ -350,21 +516,30 @@ func (r *ServiceFunctions) CalculateLotSizeByPercent(symbol string, entryPrice,
 	riskAmount := account.Balance * (riskPercent / 100.0)
 
 	// เรียกใช้ CalculateLotSize
-	return r.CalculateLotSize(symbol, entryPrice, stopLoss, riskAmount)
+	return r.CalculateLotSizeCtx(ctx, symbol, entryPrice, stopLoss, riskAmount)
 }
 
 // CalculatePipValue คำนวณมูลค่าต่อ pip สำหรับ lot size ที่กำหนด
 func (r *ServiceFunctions) CalculatePipValue(symbol string, lotSize float64) (float64, error) {
-	if r.client.token == "" {
-		return 0, fmt.Errorf("not connected")
+	return r.CalculatePipValueCtx(context.Background(), symbol, lotSize)
+}
+
+// CalculatePipValueCtx คำนวณมูลค่าต่อ pip สำหรับ lot size ที่กำหนด (รองรับ context)
+func (r *ServiceFunctions) CalculatePipValueCtx(ctx context.Context, symbol string, lotSize float64) (float64, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.CalculatePipValue", Attr("symbol", symbol), Attr("volume", lotSize))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return 0, ErrNotConnected
 	}
 
 	if lotSize <= 0 {
-		return 0, fmt.Errorf("lot size must be greater than 0")
+		return 0, fmt.Errorf("%w: lot size must be greater than 0", ErrInvalidArgument)
 	}
 
 	// ดึงข้อมูล Symbol
-	symbolParams, err := r.client.Symbol.GetParams(symbol)
+	symbolParams, err := r.client.Symbol.GetParamsCtx(ctx, symbol)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get symbol info: %w", err)
 	}
@@ -372,7 +547,7 @@ func (r *ServiceFunctions) CalculatePipValue(symbol string, lotSize float64) (fl
 	// คำนวณ Tick Value ถ้า API ส่งมาเป็น 0
 	tickValue := symbolParams.SymbolInfo.TickValue
 	if tickValue <= 0 {
-		tickValue, err = r.calculateTickValue(&symbolParams.SymbolInfo, symbol)
+		tickValue, err = r.calculateTickValue(ctx, &symbolParams.SymbolInfo, symbol)
 		if err != nil {
 			return 0, fmt.Errorf("failed to calculate tick value: %w", err)
 		}
@@ -392,7 +567,13 @@ func (r *ServiceFunctions) CalculatePipValue(symbol string, lotSize float64) (fl
 }
 
 // calculateTickValue คำนวณ Tick Value เมื่อ API ส่งมาเป็น 0
-func (r *ServiceFunctions) calculateTickValue(symbolInfo *SymbolInfo, symbol string) (float64, error) {
+func (r *ServiceFunctions) calculateTickValue(ctx context.Context, symbolInfo *SymbolInfo, symbol string) (float64, error) {
+	ctx, span := r.client.startSpan(ctx, "ServiceFunctions.calculateTickValue",
+		Attr("symbol", symbol),
+		Attr("profitCurrency", symbolInfo.ProfitCurrency),
+	)
+	defer span.End()
+
 	// ตรวจสอบว่ามีข้อมูลที่จำเป็นหรือไม่
 	if symbolInfo.Points <= 0 {
 		return 0, fmt.Errorf("invalid points value for %s", symbol)
@@ -412,7 +593,7 @@ func (r *ServiceFunctions) calculateTickValue(symbolInfo *SymbolInfo, symbol str
 	// ต้องดึง current price มาใช้คำนวณ
 	if symbolInfo.ProfitCurrency == "JPY" {
 		// ดึง current price
-		quote, err := r.client.Quote.Get(symbol)
+		quote, err := r.client.Quote.GetCtx(ctx, symbol)
 		if err != nil {
 			return 0, fmt.Errorf("failed to get quote for %s: %w", symbol, err)
 		}
@@ -442,11 +623,11 @@ func (r *ServiceFunctions) calculateTickValue(symbolInfo *SymbolInfo, symbol str
 	conversionSymbol := symbolInfo.ProfitCurrency + "USD" + suffix
 
 	// ลองดึง quote ของ conversion pair
-	conversionQuote, err := r.client.Quote.Get(conversionSymbol)
+	conversionQuote, err := r.client.Quote.GetCtx(ctx, conversionSymbol)
 	if err != nil {
 		// ถ้าไม่เจอ เช่น NZDUSD อาจจะต้องลอง USDNZD แทน
 		reverseSymbol := "USD" + symbolInfo.ProfitCurrency + suffix
-		conversionQuote, err = r.client.Quote.Get(reverseSymbol)
+		conversionQuote, err = r.client.Quote.GetCtx(ctx, reverseSymbol)
 		if err != nil {
 			return 0, fmt.Errorf("unsupported profit currency %s for symbol %s, cannot find conversion pair %s or %s",
 				symbolInfo.ProfitCurrency, symbol, conversionSymbol, reverseSymbol)
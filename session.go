@@ -0,0 +1,237 @@
+package mt5client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Session จัดการ session ของ Client แบบอัตโนมัติ
+// จำวิธีเชื่อมต่อล่าสุดไว้ และเมื่อ REST call ใดได้ ErrSessionExpired จะ re-login หนึ่งครั้ง
+// อัปเดต token ให้ทั้ง REST และ WebSocket แล้วส่ง call เดิมใหม่ (เฉพาะ endpoint ที่ retry ได้)
+//
+//	session := mt5client.NewSession(client)
+//	session.ConnectEx(1001, "password", "Demo-Server")
+//	info, err := client.Account.GetInfo() // re-login อัตโนมัติถ้า token หมดอายุ
+//
+// ถ้าเชื่อมต่อด้วย Connect/ConnectEx/ConnectProxy รหัสผ่านจะถูกเก็บไว้ใน Session เพื่อใช้ re-login
+// (ServiceFunctions.ChangePassword จะอัปเดตให้เมื่อเปลี่ยนสำเร็จ)
+// ใช้ ConnectWith กับ CredentialProvider ถ้าไม่ต้องการเก็บรหัสผ่านไว้ในหน่วยความจำ
+// ซึ่งผู้ใช้ต้องให้ provider คืนรหัสผ่านใหม่เองหลังเปลี่ยนรหัสผ่าน
+type Session struct {
+	client *Client
+
+	mu         sync.Mutex
+	connect    func(ctx context.Context) (string, error)
+	password   string // รหัสผ่านที่ใช้ re-login (ว่างถ้าใช้ CredentialProvider)
+	websockets []*WebSocketClient
+}
+
+// NewSession สร้าง Session และผูกกับ client
+func NewSession(client *Client) *Session {
+	s := &Session{client: client}
+	client.mu.Lock()
+	client.session = s
+	client.mu.Unlock()
+	return s
+}
+
+// Client คืน Client ที่ผูกอยู่
+func (s *Session) Client() *Client {
+	return s.client
+}
+
+// Connect เชื่อมต่อด้วย Connect และจำไว้สำหรับ re-login
+func (s *Session) Connect(params ConnectParams) (string, error) {
+	return s.ConnectCtx(context.Background(), params)
+}
+
+// ConnectCtx เชื่อมต่อด้วย Connect และจำไว้สำหรับ re-login (รองรับ context)
+func (s *Session) ConnectCtx(ctx context.Context, params ConnectParams) (string, error) {
+	return s.startWithPassword(ctx, params.Password, func(ctx context.Context, password string) (string, error) {
+		params.Password = password
+		return s.client.Connection.ConnectCtx(ctx, params)
+	})
+}
+
+// ConnectEx เชื่อมต่อด้วย ConnectEx และจำไว้สำหรับ re-login
+func (s *Session) ConnectEx(user int64, password, server string) (string, error) {
+	return s.ConnectExCtx(context.Background(), user, password, server)
+}
+
+// ConnectExCtx เชื่อมต่อด้วย ConnectEx และจำไว้สำหรับ re-login (รองรับ context)
+func (s *Session) ConnectExCtx(ctx context.Context, user int64, password, server string) (string, error) {
+	return s.startWithPassword(ctx, password, func(ctx context.Context, password string) (string, error) {
+		return s.client.Connection.ConnectExCtx(ctx, user, password, server)
+	})
+}
+
+// ConnectProxy เชื่อมต่อด้วย ConnectProxy และจำไว้สำหรับ re-login
+func (s *Session) ConnectProxy(params ConnectParams, proxyType, proxyHost string, proxyPort int) (string, error) {
+	return s.ConnectProxyCtx(context.Background(), params, proxyType, proxyHost, proxyPort)
+}
+
+// ConnectProxyCtx เชื่อมต่อด้วย ConnectProxy และจำไว้สำหรับ re-login (รองรับ context)
+func (s *Session) ConnectProxyCtx(ctx context.Context, params ConnectParams, proxyType, proxyHost string, proxyPort int) (string, error) {
+	return s.startWithPassword(ctx, params.Password, func(ctx context.Context, password string) (string, error) {
+		params.Password = password
+		return s.client.Connection.ConnectProxyCtx(ctx, params, proxyType, proxyHost, proxyPort)
+	})
+}
+
+// ConnectViaProxy เชื่อมต่อผ่าน proxy และ re-login ผ่าน proxy เดิม
+func (s *Session) ConnectViaProxy(params ConnectParams, proxy ProxyConfig) (string, error) {
+	return s.ConnectViaProxyCtx(context.Background(), params, proxy)
+}
+
+// ConnectViaProxyCtx เชื่อมต่อผ่าน proxy และ re-login ผ่าน proxy เดิม (รองรับ context)
+func (s *Session) ConnectViaProxyCtx(ctx context.Context, params ConnectParams, proxy ProxyConfig) (string, error) {
+	if err := proxy.Validate(); err != nil {
+		return "", err
+	}
+	return s.startWithPassword(ctx, params.Password, func(ctx context.Context, password string) (string, error) {
+		params.Password = password
+		return s.client.Connection.ConnectViaProxyCtx(ctx, params, proxy)
+	})
+}
+
+// ConnectWith เชื่อมต่อด้วย CredentialProvider (ขอข้อมูลใหม่ทุกครั้งที่ re-login)
+func (s *Session) ConnectWith(provider CredentialProvider) (string, error) {
+	return s.ConnectWithCtx(context.Background(), provider)
+}
+
+// ConnectWithCtx เชื่อมต่อด้วย CredentialProvider (รองรับ context)
+func (s *Session) ConnectWithCtx(ctx context.Context, provider CredentialProvider) (string, error) {
+	return s.start(ctx, func(ctx context.Context) (string, error) {
+		return s.client.Connection.ConnectWithCtx(ctx, provider)
+	})
+}
+
+// ConnectFailover เชื่อมต่อแบบ failover (re-login จะจัดอันดับ server ใหม่ทุกครั้ง)
+func (s *Session) ConnectFailover(user int64, password string, candidates []ServerCandidate) (*FailoverResult, error) {
+	return s.ConnectFailoverCtx(context.Background(), user, password, candidates)
+}
+
+// ConnectFailoverCtx เชื่อมต่อแบบ failover (รองรับ context)
+func (s *Session) ConnectFailoverCtx(ctx context.Context, user int64, password string, candidates []ServerCandidate) (*FailoverResult, error) {
+	var result *FailoverResult
+	_, err := s.startWithPassword(ctx, password, func(ctx context.Context, password string) (string, error) {
+		res, err := s.client.Connection.ConnectFailoverCtx(ctx, user, password, candidates)
+		result = res
+		if err != nil {
+			return "", err
+		}
+		return res.Token, nil
+	})
+	return result, err
+}
+
+// start เชื่อมต่อครั้งแรก ถ้าสำเร็จจึงจำวิธีเชื่อมต่อไว้
+func (s *Session) start(ctx context.Context, connect func(ctx context.Context) (string, error)) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, err := connect(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.connect = connect
+	s.password = ""
+	return token, nil
+}
+
+// startWithPassword เหมือน start แต่เก็บรหัสผ่านแยกไว้ เพื่อให้ re-login ใช้รหัสผ่านล่าสุดหลัง ChangePassword
+func (s *Session) startWithPassword(ctx context.Context, password string, connect func(ctx context.Context, password string) (string, error)) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, err := connect(ctx, password)
+	if err != nil {
+		return "", err
+	}
+	s.password = password
+	s.connect = func(ctx context.Context) (string, error) {
+		// ถูกเรียกขณะถือ s.mu อยู่เสมอ (ดู reconnect)
+		return connect(ctx, s.password)
+	}
+	return token, nil
+}
+
+// passwordChanged อัปเดตรหัสผ่านที่ใช้ re-login ถ้าเป็นรหัสผ่านเดียวกับที่เพิ่งถูกเปลี่ยน
+func (s *Session) passwordChanged(oldPassword, newPassword string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.connect != nil && s.password != "" && s.password == oldPassword {
+		s.password = newPassword
+	}
+}
+
+// Reconnect บังคับ re-login ด้วยวิธีเชื่อมต่อล่าสุด
+func (s *Session) Reconnect() (string, error) {
+	return s.ReconnectCtx(context.Background())
+}
+
+// ReconnectCtx บังคับ re-login ด้วยวิธีเชื่อมต่อล่าสุด (รองรับ context)
+func (s *Session) ReconnectCtx(ctx context.Context) (string, error) {
+	return s.reconnect(ctx, s.client.GetToken())
+}
+
+// Disconnect ตัดการเชื่อมต่อและลืมวิธีเชื่อมต่อ (จะไม่ re-login อีก)
+func (s *Session) Disconnect() error {
+	return s.DisconnectCtx(context.Background())
+}
+
+// DisconnectCtx ตัดการเชื่อมต่อและลืมวิธีเชื่อมต่อ (รองรับ context)
+func (s *Session) DisconnectCtx(ctx context.Context) error {
+	s.mu.Lock()
+	s.connect = nil
+	s.mu.Unlock()
+
+	return s.client.Connection.DisconnectCtx(ctx)
+}
+
+// NewWebSocketClient สร้าง WebSocketClient ที่ได้รับ token ใหม่ทุกครั้งที่ Session re-login
+func (s *Session) NewWebSocketClient() *WebSocketClient {
+	ws := s.client.NewWebSocketClient()
+	s.AttachWebSocket(ws)
+	return ws
+}
+
+// AttachWebSocket ผูก WebSocketClient ที่สร้างไว้แล้วให้ resubscribe เมื่อ Session re-login
+func (s *Session) AttachWebSocket(ws *WebSocketClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.websockets = append(s.websockets, ws)
+}
+
+// reconnect re-login ถ้า token ยังเป็น stale (ถ้า goroutine อื่น re-login ไปแล้วจะใช้ token ใหม่นั้นเลย)
+func (s *Session) reconnect(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+
+	if s.connect == nil {
+		s.mu.Unlock()
+		return "", fmt.Errorf("%w: session has no connect parameters", ErrNotConnected)
+	}
+	if token := s.client.GetToken(); token != "" && token != stale {
+		s.mu.Unlock()
+		return token, nil
+	}
+
+	token, err := s.connect(ctx)
+	if err != nil {
+		s.mu.Unlock()
+		s.client.logger.LogAttrs(ctx, slog.LevelError, "session re-login failed", slog.Any("error", err))
+		return "", err
+	}
+	websockets := append([]*WebSocketClient(nil), s.websockets...)
+	s.mu.Unlock()
+
+	s.client.logger.LogAttrs(ctx, slog.LevelInfo, "session re-established", slog.Int("websockets", len(websockets)))
+	for _, ws := range websockets {
+		ws.resubscribe()
+	}
+
+	return token, nil
+}
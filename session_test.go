@@ -0,0 +1,178 @@
+package mt5client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// expiringServer server ที่ออก token ใหม่ทุกครั้งที่ Connect และปฏิเสธ token เก่า
+type expiringServer struct {
+	mu        sync.Mutex
+	token     string
+	connects  int
+	accounts  int
+	sends     int
+	passwords []string // รหัสผ่านของแต่ละ ConnectEx
+}
+
+func (s *expiringServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/ConnectEx":
+		s.connects++
+		s.passwords = append(s.passwords, r.URL.Query().Get("password"))
+		s.token = fmt.Sprintf("token-%d", s.connects)
+		w.Write([]byte(s.token))
+	case "/ChangePassword":
+		w.Write([]byte("OK"))
+	case "/Account":
+		s.accounts++
+		if r.URL.Query().Get("id") != s.token {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"INVALID_TOKEN","message":"Invalid token"}`))
+			return
+		}
+		w.Write([]byte(`{"login":1001,"balance":100}`))
+	case "/OrderSend":
+		s.sends++
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":"INVALID_TOKEN","message":"Invalid token"}`))
+	}
+}
+
+func (s *expiringServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = "expired"
+}
+
+func TestSessionReconnectsOnExpiredToken(t *testing.T) {
+	backend := &expiringServer{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	session := NewSession(client)
+	if _, err := session.ConnectEx(1001, "secret", "Demo-Server"); err != nil {
+		t.Fatalf("ConnectEx failed: %v", err)
+	}
+
+	backend.expire()
+
+	account, err := client.Account.GetInfo()
+	if err != nil {
+		t.Fatalf("GetInfo failed: %v", err)
+	}
+	if account.Login != 1001 {
+		t.Errorf("expected login 1001, got %d", account.Login)
+	}
+	if backend.connects != 2 {
+		t.Errorf("expected one re-login, got %d connects", backend.connects)
+	}
+	if client.GetToken() != "token-2" {
+		t.Errorf("expected token-2, got %s", client.GetToken())
+	}
+}
+
+func TestSessionReloginWithoutRetryingOrderSend(t *testing.T) {
+	backend := &expiringServer{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	session := NewSession(client)
+	if _, err := session.ConnectEx(1001, "secret", "Demo-Server"); err != nil {
+		t.Fatalf("ConnectEx failed: %v", err)
+	}
+
+	_, err := client.Trading.Buy("EURUSD", 0.1, 0, 0)
+	if !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("expected ErrSessionExpired, got %v", err)
+	}
+	if backend.sends != 1 {
+		t.Errorf("expected OrderSend not to be resent, got %d sends", backend.sends)
+	}
+	if backend.connects != 2 {
+		t.Errorf("expected re-login after OrderSend, got %d connects", backend.connects)
+	}
+	if client.GetToken() != "token-2" {
+		t.Errorf("expected fresh token for the next call, got %q", client.GetToken())
+	}
+}
+
+func TestSessionReconnectsOnceForConcurrentCalls(t *testing.T) {
+	backend := &expiringServer{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	session := NewSession(client)
+	if _, err := session.ConnectEx(1001, "secret", "Demo-Server"); err != nil {
+		t.Fatalf("ConnectEx failed: %v", err)
+	}
+
+	backend.expire()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Account.GetInfo(); err != nil {
+				t.Errorf("GetInfo failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if backend.connects != 2 {
+		t.Errorf("expected a single re-login, got %d connects", backend.connects)
+	}
+}
+
+func TestSessionDisconnectStopsReconnecting(t *testing.T) {
+	backend := &expiringServer{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	session := NewSession(client)
+	if _, err := session.ConnectEx(1001, "secret", "Demo-Server"); err != nil {
+		t.Fatalf("ConnectEx failed: %v", err)
+	}
+	session.Disconnect()
+
+	client.SetToken("stale")
+	if _, err := client.Account.GetInfo(); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expected ErrSessionExpired, got %v", err)
+	}
+}
+
+func TestSessionReloginUsesChangedPassword(t *testing.T) {
+	backend := &expiringServer{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	session := NewSession(client)
+	if _, err := session.ConnectEx(1001, "secret", "Demo-Server"); err != nil {
+		t.Fatalf("ConnectEx failed: %v", err)
+	}
+	if err := client.Service.ChangePassword("secret", "new-secret"); err != nil {
+		t.Fatalf("ChangePassword failed: %v", err)
+	}
+
+	backend.expire()
+	if _, err := client.Account.GetInfo(); err != nil {
+		t.Fatalf("GetInfo failed: %v", err)
+	}
+	if len(backend.passwords) != 2 || backend.passwords[1] != "new-secret" {
+		t.Errorf("expected re-login with the new password, got %q", backend.passwords)
+	}
+}
@@ -1,6 +1,6 @@
 package mt5client
 
-import "fmt"
+import "context"
 
 // StatsService จัดการสถิติการเทรด
 type StatsService struct {
@@ -9,16 +9,25 @@ type StatsService struct {
 
 // GetTradeStats ดึงสถิติการเทรดทั้งหมด
 func (r *StatsService) GetTradeStats() (*TradeStats, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetTradeStatsCtx(context.Background())
+}
+
+// GetTradeStatsCtx ดึงสถิติการเทรดทั้งหมด (รองรับ context)
+func (r *StatsService) GetTradeStatsCtx(ctx context.Context) (*TradeStats, error) {
+	ctx, span := r.client.startSpan(ctx, "StatsService.GetTradeStats")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var stats TradeStats
-	err := r.client.get("/TradeStats", queryParams, &stats)
+	err := r.client.get(ctx, "/TradeStats", queryParams, &stats)
 	if err != nil {
 		return nil, err
 	}
@@ -28,18 +37,27 @@ func (r *StatsService) GetTradeStats() (*TradeStats, error) {
 
 // GetEquityHistory ดึงประวัติ Equity (สำหรับกราฟ)
 func (r *StatsService) GetEquityHistory(from, to string) ([]map[string]interface{}, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetEquityHistoryCtx(context.Background(), from, to)
+}
+
+// GetEquityHistoryCtx ดึงประวัติ Equity (สำหรับกราฟ) (รองรับ context)
+func (r *StatsService) GetEquityHistoryCtx(ctx context.Context, from, to string) ([]map[string]interface{}, error) {
+	ctx, span := r.client.startSpan(ctx, "StatsService.GetEquityHistory", Attr("from", from), Attr("to", to))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":   r.client.token,
+		"id":   token,
 		"from": from,
 		"to":   to,
 	}
 
 	var history []map[string]interface{}
-	err := r.client.get("/TradeStatsEquityHistory", queryParams, &history)
+	err := r.client.get(ctx, "/TradeStatsEquityHistory", queryParams, &history)
 	if err != nil {
 		return nil, err
 	}
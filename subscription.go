@@ -1,6 +1,9 @@
 package mt5client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // SubscriptionService จัดการ subscription
 type SubscriptionService struct {
@@ -9,28 +12,46 @@ type SubscriptionService struct {
 
 // Subscribe subscribe ราคาสัญลักษณ์
 func (r *SubscriptionService) Subscribe(symbol string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.SubscribeCtx(context.Background(), symbol)
+}
+
+// SubscribeCtx subscribe ราคาสัญลักษณ์ (รองรับ context)
+func (r *SubscriptionService) SubscribeCtx(ctx context.Context, symbol string) error {
+	ctx, span := r.client.startSpan(ctx, "SubscriptionService.Subscribe", Attr("symbol", symbol))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 	}
 
 	var result string
-	err := r.client.get("/Subscribe", queryParams, &result)
+	err := r.client.get(ctx, "/Subscribe", queryParams, &result)
 	return err
 }
 
 // SubscribeMany subscribe หลายสัญลักษณ์
 func (r *SubscriptionService) SubscribeMany(symbols []string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.SubscribeManyCtx(context.Background(), symbols)
+}
+
+// SubscribeManyCtx subscribe หลายสัญลักษณ์ (รองรับ context)
+func (r *SubscriptionService) SubscribeManyCtx(ctx context.Context, symbols []string) error {
+	ctx, span := r.client.startSpan(ctx, "SubscriptionService.SubscribeMany", Attr("symbols", symbols))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	for i, symbol := range symbols {
@@ -38,34 +59,52 @@ func (r *SubscriptionService) SubscribeMany(symbols []string) error {
 	}
 
 	var result string
-	err := r.client.get("/SubscribeMany", queryParams, &result)
+	err := r.client.get(ctx, "/SubscribeMany", queryParams, &result)
 	return err
 }
 
 // Unsubscribe unsubscribe สัญลักษณ์
 func (r *SubscriptionService) Unsubscribe(symbol string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.UnsubscribeCtx(context.Background(), symbol)
+}
+
+// UnsubscribeCtx unsubscribe สัญลักษณ์ (รองรับ context)
+func (r *SubscriptionService) UnsubscribeCtx(ctx context.Context, symbol string) error {
+	ctx, span := r.client.startSpan(ctx, "SubscriptionService.Unsubscribe", Attr("symbol", symbol))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 	}
 
 	var result string
-	err := r.client.get("/UnSubscribe", queryParams, &result)
+	err := r.client.get(ctx, "/UnSubscribe", queryParams, &result)
 	return err
 }
 
 // UnsubscribeMany unsubscribe หลายสัญลักษณ์
 func (r *SubscriptionService) UnsubscribeMany(symbols []string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.UnsubscribeManyCtx(context.Background(), symbols)
+}
+
+// UnsubscribeManyCtx unsubscribe หลายสัญลักษณ์ (รองรับ context)
+func (r *SubscriptionService) UnsubscribeManyCtx(ctx context.Context, symbols []string) error {
+	ctx, span := r.client.startSpan(ctx, "SubscriptionService.UnsubscribeMany", Attr("symbols", symbols))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	for i, symbol := range symbols {
@@ -73,104 +112,158 @@ func (r *SubscriptionService) UnsubscribeMany(symbols []string) error {
 	}
 
 	var result string
-	err := r.client.get("/UnSubscribeMany", queryParams, &result)
+	err := r.client.get(ctx, "/UnSubscribeMany", queryParams, &result)
 	return err
 }
 
 // SubscribeTickValue subscribe tick value
 func (r *SubscriptionService) SubscribeTickValue(symbol string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.SubscribeTickValueCtx(context.Background(), symbol)
+}
+
+// SubscribeTickValueCtx subscribe tick value (รองรับ context)
+func (r *SubscriptionService) SubscribeTickValueCtx(ctx context.Context, symbol string) error {
+	ctx, span := r.client.startSpan(ctx, "SubscriptionService.SubscribeTickValue", Attr("symbol", symbol))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 	}
 
 	var result string
-	err := r.client.get("/SubscribeTickValue", queryParams, &result)
+	err := r.client.get(ctx, "/SubscribeTickValue", queryParams, &result)
 	return err
 }
 
 // SubscribeOhlc subscribe OHLC
 func (r *SubscriptionService) SubscribeOhlc(symbol, timeframe string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.SubscribeOhlcCtx(context.Background(), symbol, timeframe)
+}
+
+// SubscribeOhlcCtx subscribe OHLC (รองรับ context)
+func (r *SubscriptionService) SubscribeOhlcCtx(ctx context.Context, symbol, timeframe string) error {
+	ctx, span := r.client.startSpan(ctx, "SubscriptionService.SubscribeOhlc", Attr("symbol", symbol), Attr("timeframe", timeframe))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
+		"id":        token,
 		"symbol":    symbol,
 		"timeframe": timeframe,
 	}
 
 	var result string
-	err := r.client.get("/SubscribeOhlc", queryParams, &result)
+	err := r.client.get(ctx, "/SubscribeOhlc", queryParams, &result)
 	return err
 }
 
 // UnsubscribeOhlc unsubscribe OHLC
 func (r *SubscriptionService) UnsubscribeOhlc(symbol, timeframe string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.UnsubscribeOhlcCtx(context.Background(), symbol, timeframe)
+}
+
+// UnsubscribeOhlcCtx unsubscribe OHLC (รองรับ context)
+func (r *SubscriptionService) UnsubscribeOhlcCtx(ctx context.Context, symbol, timeframe string) error {
+	ctx, span := r.client.startSpan(ctx, "SubscriptionService.UnsubscribeOhlc", Attr("symbol", symbol), Attr("timeframe", timeframe))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
+		"id":        token,
 		"symbol":    symbol,
 		"timeframe": timeframe,
 	}
 
 	var result string
-	err := r.client.get("/UnsubscribeOhlc", queryParams, &result)
+	err := r.client.get(ctx, "/UnsubscribeOhlc", queryParams, &result)
 	return err
 }
 
 // SubscribeOrderBook subscribe order book
 func (r *SubscriptionService) SubscribeOrderBook(symbol string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.SubscribeOrderBookCtx(context.Background(), symbol)
+}
+
+// SubscribeOrderBookCtx subscribe order book (รองรับ context)
+func (r *SubscriptionService) SubscribeOrderBookCtx(ctx context.Context, symbol string) error {
+	ctx, span := r.client.startSpan(ctx, "SubscriptionService.SubscribeOrderBook", Attr("symbol", symbol))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 	}
 
 	var result string
-	err := r.client.get("/SubscribeOrderBook", queryParams, &result)
+	err := r.client.get(ctx, "/SubscribeOrderBook", queryParams, &result)
 	return err
 }
 
 // UnsubscribeOrderBook unsubscribe order book
 func (r *SubscriptionService) UnsubscribeOrderBook(symbol string) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.UnsubscribeOrderBookCtx(context.Background(), symbol)
+}
+
+// UnsubscribeOrderBookCtx unsubscribe order book (รองรับ context)
+func (r *SubscriptionService) UnsubscribeOrderBookCtx(ctx context.Context, symbol string) error {
+	ctx, span := r.client.startSpan(ctx, "SubscriptionService.UnsubscribeOrderBook", Attr("symbol", symbol))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 	}
 
 	var result string
-	err := r.client.get("/UnsubscribeOrderBook", queryParams, &result)
+	err := r.client.get(ctx, "/UnsubscribeOrderBook", queryParams, &result)
 	return err
 }
 
 // SubscribeMarketWatch subscribe market watch
 func (r *SubscriptionService) SubscribeMarketWatch() error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.SubscribeMarketWatchCtx(context.Background())
+}
+
+// SubscribeMarketWatchCtx subscribe market watch (รองรับ context)
+func (r *SubscriptionService) SubscribeMarketWatchCtx(ctx context.Context) error {
+	ctx, span := r.client.startSpan(ctx, "SubscriptionService.SubscribeMarketWatch")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var result string
-	err := r.client.get("/SubscribeMarketWatch", queryParams, &result)
+	err := r.client.get(ctx, "/SubscribeMarketWatch", queryParams, &result)
 	return err
 }
 
@@ -179,5 +272,5 @@ func (r *SubscriptionService) GetWebSocketURL() (string, error) {
 	// WebSocket endpoints จะใช้ผ่าน /On* paths
 	// เช่น /OnQuote, /OnOrderUpdate, etc.
 	// ต้องใช้ WebSocket client แยกต่างหาก
-	return r.client.baseURL, nil
+	return r.client.baseURL + r.client.basePath, nil
 }
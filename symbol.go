@@ -1,6 +1,9 @@
 package mt5client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // SymbolService จัดการสัญลักษณ์
 type SymbolService struct {
@@ -9,16 +12,25 @@ type SymbolService struct {
 
 // GetList ดึงรายการสัญลักษณ์ทั้งหมด
 func (r *SymbolService) GetList() ([]string, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetListCtx(context.Background())
+}
+
+// GetListCtx ดึงรายการสัญลักษณ์ทั้งหมด (รองรับ context)
+func (r *SymbolService) GetListCtx(ctx context.Context) ([]string, error) {
+	ctx, span := r.client.startSpan(ctx, "SymbolService.GetList")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var symbols []string
-	err := r.client.get("/SymbolList", queryParams, &symbols)
+	err := r.client.get(ctx, "/SymbolList", queryParams, &symbols)
 	if err != nil {
 		return nil, err
 	}
@@ -28,17 +40,26 @@ func (r *SymbolService) GetList() ([]string, error) {
 
 // GetParams ดึงพารามิเตอร์ของสัญลักษณ์
 func (r *SymbolService) GetParams(symbol string) (*SymbolParams, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetParamsCtx(context.Background(), symbol)
+}
+
+// GetParamsCtx ดึงพารามิเตอร์ของสัญลักษณ์ (รองรับ context)
+func (r *SymbolService) GetParamsCtx(ctx context.Context, symbol string) (*SymbolParams, error) {
+	ctx, span := r.client.startSpan(ctx, "SymbolService.GetParams", Attr("symbol", symbol))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 	}
 
 	var symbolParams SymbolParams
-	err := r.client.get("/SymbolParams", queryParams, &symbolParams)
+	err := r.client.get(ctx, "/SymbolParams", queryParams, &symbolParams)
 	if err != nil {
 		return nil, err
 	}
@@ -48,12 +69,21 @@ func (r *SymbolService) GetParams(symbol string) (*SymbolParams, error) {
 
 // GetParamsMany ดึงพารามิเตอร์หลายสัญลักษณ์
 func (r *SymbolService) GetParamsMany(symbols []string) ([]SymbolParams, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetParamsManyCtx(context.Background(), symbols)
+}
+
+// GetParamsManyCtx ดึงพารามิเตอร์หลายสัญลักษณ์ (รองรับ context)
+func (r *SymbolService) GetParamsManyCtx(ctx context.Context, symbols []string) ([]SymbolParams, error) {
+	ctx, span := r.client.startSpan(ctx, "SymbolService.GetParamsMany", Attr("symbols", symbols))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	for i, symbol := range symbols {
@@ -61,7 +91,7 @@ func (r *SymbolService) GetParamsMany(symbols []string) ([]SymbolParams, error)
 	}
 
 	var symbolParams []SymbolParams
-	err := r.client.get("/SymbolParamsMany", queryParams, &symbolParams)
+	err := r.client.get(ctx, "/SymbolParamsMany", queryParams, &symbolParams)
 	if err != nil {
 		return nil, err
 	}
@@ -71,17 +101,26 @@ func (r *SymbolService) GetParamsMany(symbols []string) ([]SymbolParams, error)
 
 // GetSessions ดึงข้อมูลเซสชันของสัญลักษณ์
 func (r *SymbolService) GetSessions(symbol string) (map[string]interface{}, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetSessionsCtx(context.Background(), symbol)
+}
+
+// GetSessionsCtx ดึงข้อมูลเซสชันของสัญลักษณ์ (รองรับ context)
+func (r *SymbolService) GetSessionsCtx(ctx context.Context, symbol string) (map[string]interface{}, error) {
+	ctx, span := r.client.startSpan(ctx, "SymbolService.GetSessions", Attr("symbol", symbol))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"symbol": symbol,
 	}
 
 	var sessions map[string]interface{}
-	err := r.client.get("/SymbolSessions", queryParams, &sessions)
+	err := r.client.get(ctx, "/SymbolSessions", queryParams, &sessions)
 	if err != nil {
 		return nil, err
 	}
@@ -91,16 +130,25 @@ func (r *SymbolService) GetSessions(symbol string) (map[string]interface{}, erro
 
 // GetAll ดึงสัญลักษณ์ทั้งหมด (รวมข้อมูล)
 func (r *SymbolService) GetAll() ([]SymbolInfo, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetAllCtx(context.Background())
+}
+
+// GetAllCtx ดึงสัญลักษณ์ทั้งหมด (รวมข้อมูล) (รองรับ context)
+func (r *SymbolService) GetAllCtx(ctx context.Context) ([]SymbolInfo, error) {
+	ctx, span := r.client.startSpan(ctx, "SymbolService.GetAll")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var symbols []SymbolInfo
-	err := r.client.get("/Symbols", queryParams, &symbols)
+	err := r.client.get(ctx, "/Symbols", queryParams, &symbols)
 	if err != nil {
 		return nil, err
 	}
@@ -110,16 +158,25 @@ func (r *SymbolService) GetAll() ([]SymbolInfo, error) {
 
 // GetSubscribed ดึงสัญลักษณ์ที่ subscribe อยู่
 func (r *SymbolService) GetSubscribed() ([]string, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.GetSubscribedCtx(context.Background())
+}
+
+// GetSubscribedCtx ดึงสัญลักษณ์ที่ subscribe อยู่ (รองรับ context)
+func (r *SymbolService) GetSubscribedCtx(ctx context.Context) ([]string, error) {
+	ctx, span := r.client.startSpan(ctx, "SymbolService.GetSubscribed")
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id": r.client.token,
+		"id": token,
 	}
 
 	var symbols []string
-	err := r.client.get("/SubscribedSymbols", queryParams, &symbols)
+	err := r.client.get(ctx, "/SubscribedSymbols", queryParams, &symbols)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package mt5client
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -65,12 +66,12 @@ func (sn *SymbolNormalizer) SetCacheDuration(duration time.Duration) {
 }
 
 // refreshCache อัพเดท cache symbol list
-func (sn *SymbolNormalizer) refreshCache() error {
+func (sn *SymbolNormalizer) refreshCache(ctx context.Context) error {
 	sn.mu.Lock()
 	defer sn.mu.Unlock()
 
 	// ดึงรายการ symbol ทั้งหมด
-	symbols, err := sn.client.Symbol.GetList()
+	symbols, err := sn.client.Symbol.GetListCtx(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get symbol list: %w", err)
 	}
@@ -98,11 +99,16 @@ func (sn *SymbolNormalizer) isCacheValid() bool {
 
 // Normalize แปลง symbol เป็นชื่อที่ใช้จริงในโบรกเกอร์
 func (sn *SymbolNormalizer) Normalize(inputSymbol string) (string, error) {
+	return sn.NormalizeCtx(context.Background(), inputSymbol)
+}
+
+// NormalizeCtx แปลง symbol เป็นชื่อที่ใช้จริงในโบรกเกอร์ (รองรับ context)
+func (sn *SymbolNormalizer) NormalizeCtx(ctx context.Context, inputSymbol string) (string, error) {
 	// ทำให้เป็นตัวพิมพ์ใหญ่
 	inputSymbol = strings.ToUpper(strings.TrimSpace(inputSymbol))
 
 	if inputSymbol == "" {
-		return "", fmt.Errorf("input symbol is empty")
+		return "", fmt.Errorf("%w: input symbol is empty", ErrInvalidArgument)
 	}
 
 	// 1. เช็คใน cache map ก่อน (fastest)
@@ -115,7 +121,7 @@ func (sn *SymbolNormalizer) Normalize(inputSymbol string) (string, error) {
 
 	// 2. Refresh cache ถ้าหมดอายุ
 	if !sn.isCacheValid() {
-		if err := sn.refreshCache(); err != nil {
+		if err := sn.refreshCache(ctx); err != nil {
 			return "", err
 		}
 	}
@@ -210,7 +216,7 @@ func (sn *SymbolNormalizer) findSymbol(input string) (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("symbol '%s' not found in broker", input)
+	return "", fmt.Errorf("%w: '%s' not found in broker", ErrSymbolNotFound, input)
 }
 
 // isSuffix เช็คว่า string เป็น suffix ของโบรกเกอร์หรือไม่
@@ -239,10 +245,18 @@ func isSuffix(s string) bool {
 
 // NormalizeMany แปลงหลาย symbols พร้อมกัน
 func (sn *SymbolNormalizer) NormalizeMany(inputSymbols []string) (map[string]string, error) {
+	return sn.NormalizeManyCtx(context.Background(), inputSymbols)
+}
+
+// NormalizeManyCtx แปลงหลาย symbols พร้อมกัน (รองรับ context)
+func (sn *SymbolNormalizer) NormalizeManyCtx(ctx context.Context, inputSymbols []string) (map[string]string, error) {
 	result := make(map[string]string)
 
 	for _, input := range inputSymbols {
-		normalized, err := sn.Normalize(input)
+		normalized, err := sn.NormalizeCtx(ctx, input)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		if err != nil {
 			result[input] = "" // ไม่เจอ
 		} else {
@@ -255,8 +269,13 @@ func (sn *SymbolNormalizer) NormalizeMany(inputSymbols []string) (map[string]str
 
 // GetAvailableSymbols ดึงรายการ symbol ทั้งหมดที่มี
 func (sn *SymbolNormalizer) GetAvailableSymbols() ([]string, error) {
+	return sn.GetAvailableSymbolsCtx(context.Background())
+}
+
+// GetAvailableSymbolsCtx ดึงรายการ symbol ทั้งหมดที่มี (รองรับ context)
+func (sn *SymbolNormalizer) GetAvailableSymbolsCtx(ctx context.Context) ([]string, error) {
 	if !sn.isCacheValid() {
-		if err := sn.refreshCache(); err != nil {
+		if err := sn.refreshCache(ctx); err != nil {
 			return nil, err
 		}
 	}
@@ -0,0 +1,52 @@
+package mt5client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNormalizeCtxCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL, WithLogger(nil))
+	client.SetToken("token")
+	normalizer := client.NewSymbolNormalizer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := normalizer.NormalizeCtx(ctx, "EURUSD"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestNormalizeCtx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`["EURUSD.m","XAUUSD.m"]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithLogger(nil))
+	client.SetToken("token")
+	normalizer := client.NewSymbolNormalizer()
+
+	symbol, err := normalizer.NormalizeCtx(context.Background(), "eurusd")
+	if err != nil {
+		t.Fatalf("NormalizeCtx failed: %v", err)
+	}
+	if symbol != "EURUSD.m" {
+		t.Errorf("expected EURUSD.m, got %s", symbol)
+	}
+}
@@ -0,0 +1,55 @@
+package mt5client
+
+import "context"
+
+// Tracer สร้าง span สำหรับ tracing (ใช้ bridge ไปยัง backend เช่น OpenTelemetry)
+type Tracer interface {
+	// Start เริ่ม span ใหม่เป็นลูกของ span ใน ctx และคืน ctx ที่มี span ใหม่
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span span หนึ่งช่วงการทำงาน
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute key/value ที่แนบไปกับ span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr สร้าง Attribute
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// WithTracer กำหนด Tracer (default: ไม่ trace)
+func WithTracer(tracer Tracer) Option {
+	return func(c *Client) {
+		if tracer != nil {
+			c.tracer = tracer
+		}
+	}
+}
+
+// startSpan เริ่ม span ผ่าน tracer ของ Client
+func (r *Client) startSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return r.tracer.Start(ctx, name, attrs...)
+}
+
+// noopTracer Tracer ที่ไม่ทำอะไร
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// noopSpan Span ที่ไม่ทำอะไร
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
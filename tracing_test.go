@@ -0,0 +1,92 @@
+package mt5client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type recordedSpan struct {
+	name   string
+	parent string
+	attrs  map[string]interface{}
+	ended  bool
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type spanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	span := &recordedSpan{name: name, attrs: map[string]interface{}{}}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		span.parent = parent.name
+	}
+	span.SetAttributes(attrs...)
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) {}
+
+func (s *recordedSpan) End() { s.ended = true }
+
+func TestTracingCompositeOperation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/SymbolParams":
+			w.Write([]byte(`{"symbol":"EURUSD","symbolInfo":{"points":0.00001,"tickValue":1,"digits":5}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client := NewClient(server.URL, WithTracer(tracer))
+	client.SetToken("token")
+
+	if _, err := client.Service.CalculateLotSize("EURUSD", 1.1, 1.099, 100); err != nil {
+		t.Fatalf("CalculateLotSize failed: %v", err)
+	}
+
+	want := []struct{ name, parent string }{
+		{"ServiceFunctions.CalculateLotSize", ""},
+		{"SymbolService.GetParams", "ServiceFunctions.CalculateLotSize"},
+		{"HTTP GET /SymbolParams", "SymbolService.GetParams"},
+	}
+	if len(tracer.spans) != len(want) {
+		t.Fatalf("expected %d spans, got %d", len(want), len(tracer.spans))
+	}
+	for i, w := range want {
+		span := tracer.spans[i]
+		if span.name != w.name || span.parent != w.parent {
+			t.Errorf("span %d: expected %s (parent %q), got %s (parent %q)", i, w.name, w.parent, span.name, span.parent)
+		}
+		if !span.ended {
+			t.Errorf("span %s was not ended", span.name)
+		}
+	}
+
+	if got := tracer.spans[0].attrs["symbol"]; got != "EURUSD" {
+		t.Errorf("expected symbol attribute EURUSD, got %v", got)
+	}
+	if got := tracer.spans[2].attrs["http.status_code"]; got != 200 {
+		t.Errorf("expected status code 200, got %v", got)
+	}
+}
@@ -1,6 +1,9 @@
 package mt5client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // TradingService จัดการการเทรด
 type TradingService struct {
@@ -9,25 +12,67 @@ type TradingService struct {
 
 // Send ส่งคำสั่งซื้อขาย
 func (r *TradingService) Send(req OrderRequest) (*Order, error) {
-	if r.client.token == "" {
-		return nil, fmt.Errorf("not connected")
+	return r.SendCtx(context.Background(), req)
+}
+
+// SendCtx ส่งคำสั่งซื้อขาย (รองรับ context)
+func (r *TradingService) SendCtx(ctx context.Context, req OrderRequest) (*Order, error) {
+	ctx, span := r.client.startSpan(ctx, "TradingService.Send", Attr("symbol", req.Symbol), Attr("type", req.Type), Attr("volume", req.Volume))
+	defer span.End()
+
+	if r.client.idempotentOrders || req.ClientOrderID != "" {
+		return r.sendIdempotent(ctx, req)
+	}
+	return r.sendOrder(ctx, req)
+}
+
+// sendOrder ตรวจสอบและส่ง /OrderSend หนึ่งครั้ง
+func (r *TradingService) sendOrder(ctx context.Context, req OrderRequest) (*Order, error) {
+	if r.client.GetToken() == "" {
+		return nil, ErrNotConnected
+	}
+
+	queryParams, err := r.prepareOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return r.postOrder(ctx, queryParams)
+}
+
+// prepareOrder ตรวจสอบ req (รวม OrderValidator ถ้าเปิดไว้) แล้วสร้าง query ของ /OrderSend (ยังไม่มี token)
+func (r *TradingService) prepareOrder(ctx context.Context, req OrderRequest) (map[string]string, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	volumeFormat, priceFormat := "%.2f", "%.5f"
+	if validator := r.client.orderValidator; validator != nil {
+		params, err := validator.Params(ctx, req.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		if req, err = validator.validate(ctx, req, params); err != nil {
+			return nil, err
+		}
+		// ใช้ step/digits ชุดเดียวกับที่ validate ปัดค่าไว้
+		volumeFormat = fmt.Sprintf("%%.%df", decimalPlaces(lotStep(params.SymbolGroup)))
+		priceFormat = fmt.Sprintf("%%.%df", priceDigits(params.SymbolInfo))
 	}
 
 	queryParams := map[string]string{
-		"id":        r.client.token,
 		"symbol":    req.Symbol,
-		"operation": req.Type,
-		"volume":    fmt.Sprintf("%.2f", req.Volume),
+		"operation": string(req.Type),
+		"volume":    fmt.Sprintf(volumeFormat, req.Volume),
 	}
 
 	if req.Price > 0 {
-		queryParams["price"] = fmt.Sprintf("%.5f", req.Price)
+		queryParams["price"] = fmt.Sprintf(priceFormat, req.Price)
 	}
 	if req.StopLoss > 0 {
-		queryParams["stoploss"] = fmt.Sprintf("%.5f", req.StopLoss)
+		queryParams["stoploss"] = fmt.Sprintf(priceFormat, req.StopLoss)
 	}
 	if req.TakeProfit > 0 {
-		queryParams["takeprofit"] = fmt.Sprintf("%.5f", req.TakeProfit)
+		queryParams["takeprofit"] = fmt.Sprintf(priceFormat, req.TakeProfit)
 	}
 
 	if req.PlacedType != "" {
@@ -36,9 +81,44 @@ func (r *TradingService) Send(req OrderRequest) (*Order, error) {
 	if req.Comment != "" {
 		queryParams["comment"] = req.Comment
 	}
+	if req.MagicNumber != 0 {
+		queryParams["expertID"] = fmt.Sprintf("%d", req.MagicNumber)
+	}
+	if req.StopLimitPrice > 0 {
+		queryParams["stoplimit"] = fmt.Sprintf(priceFormat, req.StopLimitPrice)
+	}
+	if req.Deviation > 0 {
+		queryParams["slippage"] = fmt.Sprintf("%d", req.Deviation)
+	}
+	if req.Expiration != nil {
+		queryParams["expirationType"] = string(req.Expiration.Mode)
+		if req.Expiration.Mode == ExpirationSpecified {
+			// MT5 format (ไม่มี timezone) ใช้วัน/เวลาของ Time ตรงๆ ผู้เรียกต้องส่งเวลาของ server มา (ดู ExpireAtIn)
+			queryParams["expiration"] = req.Expiration.Time.Format("2006-01-02T15:04:05")
+		}
+	}
+	if req.FillPolicy != "" {
+		queryParams["fillPolicy"] = string(req.FillPolicy)
+	}
+
+	return queryParams, nil
+}
+
+// postOrder ส่ง /OrderSend ด้วย query ที่เตรียมไว้ (ใส่ token ล่าสุดทุกครั้ง)
+func (r *TradingService) postOrder(ctx context.Context, queryParams map[string]string) (*Order, error) {
+	token := r.client.GetToken()
+	if token == "" {
+		return nil, ErrNotConnected
+	}
+
+	query := make(map[string]string, len(queryParams)+1)
+	for k, v := range queryParams {
+		query[k] = v
+	}
+	query["id"] = token
 
 	var result Order
-	err := r.client.get("/OrderSend", queryParams, &result)
+	err := r.client.get(ctx, "/OrderSend", query, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -48,9 +128,17 @@ func (r *TradingService) Send(req OrderRequest) (*Order, error) {
 
 // Buy ซื้อทันที
 func (r *TradingService) Buy(symbol string, volume float64, sl, tp float64) (*Order, error) {
-	return r.Send(OrderRequest{
+	return r.BuyCtx(context.Background(), symbol, volume, sl, tp)
+}
+
+// BuyCtx ซื้อทันที (รองรับ context)
+func (r *TradingService) BuyCtx(ctx context.Context, symbol string, volume float64, sl, tp float64) (*Order, error) {
+	ctx, span := r.client.startSpan(ctx, "TradingService.Buy", Attr("symbol", symbol), Attr("volume", volume))
+	defer span.End()
+
+	return r.SendCtx(ctx, OrderRequest{
 		Symbol:     symbol,
-		Type:       "Buy",
+		Type:       OrderBuy,
 		Volume:     volume,
 		StopLoss:   sl,
 		TakeProfit: tp,
@@ -59,9 +147,17 @@ func (r *TradingService) Buy(symbol string, volume float64, sl, tp float64) (*Or
 
 // Sell ขายทันที
 func (r *TradingService) Sell(symbol string, volume float64, sl, tp float64) (*Order, error) {
-	return r.Send(OrderRequest{
+	return r.SellCtx(context.Background(), symbol, volume, sl, tp)
+}
+
+// SellCtx ขายทันที (รองรับ context)
+func (r *TradingService) SellCtx(ctx context.Context, symbol string, volume float64, sl, tp float64) (*Order, error) {
+	ctx, span := r.client.startSpan(ctx, "TradingService.Sell", Attr("symbol", symbol), Attr("volume", volume))
+	defer span.End()
+
+	return r.SendCtx(ctx, OrderRequest{
 		Symbol:     symbol,
-		Type:       "Sell",
+		Type:       OrderSell,
 		Volume:     volume,
 		StopLoss:   sl,
 		TakeProfit: tp,
@@ -70,12 +166,21 @@ func (r *TradingService) Sell(symbol string, volume float64, sl, tp float64) (*O
 
 // Modify แก้ไขคำสั่ง
 func (r *TradingService) Modify(ticket int64, price, sl, tp float64) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.ModifyCtx(context.Background(), ticket, price, sl, tp)
+}
+
+// ModifyCtx แก้ไขคำสั่ง (รองรับ context)
+func (r *TradingService) ModifyCtx(ctx context.Context, ticket int64, price, sl, tp float64) error {
+	ctx, span := r.client.startSpan(ctx, "TradingService.Modify", Attr("ticket", ticket))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"ticket": fmt.Sprintf("%d", ticket),
 	}
 
@@ -90,18 +195,27 @@ func (r *TradingService) Modify(ticket int64, price, sl, tp float64) error {
 	}
 
 	var result string
-	err := r.client.get("/OrderModify", queryParams, &result)
+	err := r.client.get(ctx, "/OrderModify", queryParams, &result)
 	return err
 }
 
 // Close ปิดคำสั่ง
 func (r *TradingService) Close(ticket int64, volume float64) error {
-	if r.client.token == "" {
-		return fmt.Errorf("not connected")
+	return r.CloseCtx(context.Background(), ticket, volume)
+}
+
+// CloseCtx ปิดคำสั่ง (รองรับ context)
+func (r *TradingService) CloseCtx(ctx context.Context, ticket int64, volume float64) error {
+	ctx, span := r.client.startSpan(ctx, "TradingService.Close", Attr("ticket", ticket), Attr("volume", volume))
+	defer span.End()
+
+	token := r.client.GetToken()
+	if token == "" {
+		return ErrNotConnected
 	}
 
 	queryParams := map[string]string{
-		"id":     r.client.token,
+		"id":     token,
 		"ticket": fmt.Sprintf("%d", ticket),
 	}
 
@@ -110,6 +224,6 @@ func (r *TradingService) Close(ticket int64, volume float64) error {
 	}
 
 	var result string
-	err := r.client.get("/OrderClose", queryParams, &result)
+	err := r.client.get(ctx, "/OrderClose", queryParams, &result)
 	return err
 }
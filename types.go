@@ -34,7 +34,7 @@ type Account struct {
 type Order struct {
 	Ticket            int64     `json:"ticket"`
 	Profit            float64   `json:"profit"`
-	OrderType         string    `json:"orderType"`
+	OrderType         OrderType `json:"orderType"`
 	Symbol            string    `json:"symbol"`
 	Lots              float64   `gorm:"not null"`
 	OpenPrice         float64   `json:"openPrice"`
@@ -52,6 +52,7 @@ type Order struct {
 	Fee               float64   `json:"fee"`
 	State             string    `json:"state"`
 	Comment           string    `json:"comment"`
+	MagicNumber       int64     `json:"magicNumber"`
 }
 
 // UnmarshalJSON custom unmarshal สำหรับ Order
@@ -61,6 +62,7 @@ func (o *Order) UnmarshalJSON(data []byte) error {
 	aux := &struct {
 		OpenTime  string `json:"openTime"`  // ← ดักจับเป็น string
 		CloseTime string `json:"closeTime"` // ← ดักจับเป็น string
+		ExpertID  int64  `json:"expertId"`  // ← server บางรุ่นส่ง magic number ในชื่อนี้
 		*Alias
 	}{
 		Alias: (*Alias)(o),
@@ -70,6 +72,10 @@ func (o *Order) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	if o.MagicNumber == 0 {
+		o.MagicNumber = aux.ExpertID
+	}
+
 	// แปลงจาก timestamp → time.Time (ไม่สนใจ string)
 	if o.OpenTimestampUTC > 0 {
 		o.OpenTime = time.UnixMilli(o.OpenTimestampUTC)
@@ -84,14 +90,23 @@ func (o *Order) UnmarshalJSON(data []byte) error {
 
 // OrderRequest พารามิเตอร์สำหรับส่งคำสั่ง
 type OrderRequest struct {
-	Symbol     string  `json:"symbol"`
-	Type       string  `json:"type"`
-	Volume     float64 `json:"volume"`
-	Price      float64 `json:"price,omitempty"`
-	StopLoss   float64 `json:"stoploss,omitempty"`
-	TakeProfit float64 `json:"takeprofit,omitempty"`
-	PlacedType string  `json:"placedType,omitempty"`
-	Comment    string  `json:"comment,omitempty"`
+	Symbol     string    `json:"symbol"`
+	Type       OrderType `json:"type"`
+	Volume     float64   `json:"volume"`
+	Price      float64   `json:"price,omitempty"`
+	StopLoss   float64   `json:"stoploss,omitempty"`
+	TakeProfit float64   `json:"takeprofit,omitempty"`
+	PlacedType string    `json:"placedType,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	// MagicNumber รหัสของกลยุทธ์/bot ที่ส่งคำสั่ง ใช้แยก position ของแต่ละ bot ในบัญชีเดียวกัน
+	MagicNumber int64 `json:"magicNumber,omitempty"`
+	// ClientOrderID รหัสคำสั่งฝั่ง client สำหรับส่งแบบ idempotent (ดู TradingService.SendIdempotent)
+	ClientOrderID string `json:"clientOrderId,omitempty"`
+
+	StopLimitPrice float64     `json:"stoplimit,omitempty"`  // ราคาที่จะวาง limit order เมื่อราคาแตะ Price (เฉพาะ StopLimit)
+	Deviation      int         `json:"slippage,omitempty"`   // slippage สูงสุด (points)
+	Expiration     *Expiration `json:"expiration,omitempty"` // เฉพาะ pending (nil = ค่า default ของ server)
+	FillPolicy     FillPolicy  `json:"fillPolicy,omitempty"`
 }
 
 // TradeResult ผลลัพธ์การเทรด
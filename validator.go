@@ -0,0 +1,278 @@
+package mt5client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ValidationError คำสั่งไม่ผ่านการตรวจสอบก่อนส่ง (errors.Is(err, ErrInvalidArgument) เป็นจริง)
+type ValidationError struct {
+	Symbol string
+	Field  string // เช่น "volume", "price", "stoploss", "takeprofit", "type"
+	Reason string
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid order for %s: %s: %s", e.Symbol, e.Field, e.Reason)
+}
+
+// Unwrap ให้ errors.Is(err, ErrInvalidArgument) ใช้ได้
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidArgument
+}
+
+// cachedParams SymbolParams ที่ cache ไว้
+type cachedParams struct {
+	params *SymbolParams
+	at     time.Time
+}
+
+// OrderValidator ตรวจสอบและปรับ OrderRequest ตาม SymbolParams ก่อนส่ง /OrderSend
+//   - ปัด volume ลงตาม LotsStep และตรวจ MinLots/MaxLots
+//   - ปัดราคาตาม TickSize และ Digits
+//   - ตรวจระยะ SL/TP ขั้นต่ำตาม SymbolGroup.Sl/Tp (points) และ TradeMode
+//
+// เปิดใช้กับทุกคำสั่งด้วย WithOrderValidation หรือเรียก Validate เองก็ได้
+type OrderValidator struct {
+	client        *Client
+	mu            sync.RWMutex
+	cache         map[string]cachedParams
+	cacheDuration time.Duration
+}
+
+// NewOrderValidator สร้าง validator (cache SymbolParams 1 ชั่วโมง)
+func (r *Client) NewOrderValidator() *OrderValidator {
+	return &OrderValidator{
+		client:        r,
+		cache:         make(map[string]cachedParams),
+		cacheDuration: time.Hour,
+	}
+}
+
+// OrderValidator คืน validator ที่เปิดด้วย WithOrderValidation (nil ถ้าไม่ได้เปิด)
+func (r *Client) OrderValidator() *OrderValidator {
+	return r.orderValidator
+}
+
+// SetCacheDuration กำหนดระยะเวลา cache ของ SymbolParams
+func (v *OrderValidator) SetCacheDuration(duration time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.cacheDuration = duration
+}
+
+// Invalidate ลบ cache ของ symbol (ว่าง = ลบทั้งหมด)
+func (v *OrderValidator) Invalidate(symbol string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if symbol == "" {
+		v.cache = make(map[string]cachedParams)
+		return
+	}
+	delete(v.cache, symbol)
+}
+
+// Params คืน SymbolParams จาก cache หรือดึงใหม่เมื่อหมดอายุ
+func (v *OrderValidator) Params(ctx context.Context, symbol string) (*SymbolParams, error) {
+	v.mu.RLock()
+	cached, ok := v.cache[symbol]
+	valid := ok && time.Since(cached.at) < v.cacheDuration
+	v.mu.RUnlock()
+	if valid {
+		return cached.params, nil
+	}
+
+	params, err := v.client.Symbol.GetParamsCtx(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	v.cache[symbol] = cachedParams{params: params, at: time.Now()}
+	v.mu.Unlock()
+
+	return params, nil
+}
+
+// Validate ตรวจสอบและคืน OrderRequest ที่ปรับ volume/ราคาแล้ว
+func (v *OrderValidator) Validate(req OrderRequest) (OrderRequest, error) {
+	return v.ValidateCtx(context.Background(), req)
+}
+
+// ValidateCtx ตรวจสอบและคืน OrderRequest ที่ปรับ volume/ราคาแล้ว (รองรับ context)
+func (v *OrderValidator) ValidateCtx(ctx context.Context, req OrderRequest) (OrderRequest, error) {
+	params, err := v.Params(ctx, req.Symbol)
+	if err != nil {
+		return req, err
+	}
+	return v.validate(ctx, req, params)
+}
+
+// validate ตรวจสอบ req กับ params
+func (v *OrderValidator) validate(ctx context.Context, req OrderRequest, params *SymbolParams) (OrderRequest, error) {
+	info, group := params.SymbolInfo, params.SymbolGroup
+	invalid := func(field, format string, args ...interface{}) error {
+		return &ValidationError{Symbol: req.Symbol, Field: field, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if err := checkTradeMode(req.Type, group.TradeMode); err != "" {
+		return req, invalid("type", "%s", err)
+	}
+	if err := checkSymbolPolicies(req, group); err != nil {
+		return req, err
+	}
+
+	// volume
+	step := lotStep(group)
+	volume := roundToStep(req.Volume, step, math.Floor)
+	switch {
+	case volume <= 0:
+		return req, invalid("volume", "%g is below lot step %g", req.Volume, step)
+	case group.MinLots > 0 && volume < group.MinLots:
+		return req, invalid("volume", "%g is below minimum %g", req.Volume, group.MinLots)
+	case group.MaxLots > 0 && volume > group.MaxLots:
+		return req, invalid("volume", "%g is above maximum %g", req.Volume, group.MaxLots)
+	}
+	req.Volume = volume
+
+	// ราคา
+	tick := info.TickSize
+	if tick <= 0 {
+		tick = info.Points
+	}
+	digits := priceDigits(info)
+	roundPrice := func(price float64) float64 {
+		if price <= 0 {
+			return price
+		}
+		if tick > 0 {
+			price = roundToStep(price, tick, math.Round)
+		}
+		return roundToDigits(price, digits)
+	}
+	req.Price = roundPrice(req.Price)
+	req.StopLimitPrice = roundPrice(req.StopLimitPrice)
+	req.StopLoss = roundPrice(req.StopLoss)
+	req.TakeProfit = roundPrice(req.TakeProfit)
+
+	// ระยะ SL/TP
+	if req.StopLoss <= 0 && req.TakeProfit <= 0 {
+		return req, nil
+	}
+
+	entry := req.Price
+	if req.StopLimitPrice > 0 {
+		entry = req.StopLimitPrice
+	}
+	if !req.Type.IsPending() {
+		quote, err := v.client.Quote.GetCtx(ctx, req.Symbol)
+		if err != nil {
+			return req, fmt.Errorf("failed to get quote for stop validation: %w", err)
+		}
+		entry = quote.Bid
+		if req.Type.IsLong() {
+			entry = quote.Ask
+		}
+	}
+
+	point := info.Points
+	if point <= 0 {
+		point = math.Pow10(-digits)
+	}
+	// ระยะขั้นต่ำ (ลบครึ่ง point กัน floating point error)
+	slLevel := float64(group.Sl)*point - point/2
+	tpLevel := float64(group.Tp)*point - point/2
+
+	direction := 1.0
+	if !req.Type.IsLong() {
+		direction = -1.0
+	}
+
+	if req.StopLoss > 0 {
+		distance := (entry - req.StopLoss) * direction
+		if distance <= 0 {
+			return req, invalid("stoploss", "%g is on the wrong side of entry %g", req.StopLoss, entry)
+		}
+		if group.Sl > 0 && distance < slLevel {
+			return req, invalid("stoploss", "%g is closer than %d points to entry %g", req.StopLoss, group.Sl, entry)
+		}
+	}
+	if req.TakeProfit > 0 {
+		distance := (req.TakeProfit - entry) * direction
+		if distance <= 0 {
+			return req, invalid("takeprofit", "%g is on the wrong side of entry %g", req.TakeProfit, entry)
+		}
+		if group.Tp > 0 && distance < tpLevel {
+			return req, invalid("takeprofit", "%g is closer than %d points to entry %g", req.TakeProfit, group.Tp, entry)
+		}
+	}
+
+	return req, nil
+}
+
+// lotStep LotsStep ของสัญลักษณ์ (0.01 ถ้า server ไม่ได้ส่งมา)
+func lotStep(group SymbolGroup) float64 {
+	if group.LotsStep > 0 {
+		return group.LotsStep
+	}
+	return 0.01
+}
+
+// priceDigits จำนวนทศนิยมของราคา (ใช้ TickSize/Points หรือ 5 ถ้า server ไม่ได้ส่ง Digits มา)
+func priceDigits(info SymbolInfo) int {
+	if info.Digits > 0 {
+		return info.Digits
+	}
+	tick := info.TickSize
+	if tick <= 0 {
+		tick = info.Points
+	}
+	if tick > 0 {
+		return decimalPlaces(tick)
+	}
+	return 5
+}
+
+// checkTradeMode ตรวจสอบ TradeMode ของสัญลักษณ์ (คืนข้อความเหตุผลถ้าไม่อนุญาต)
+func checkTradeMode(orderType OrderType, tradeMode string) string {
+	mode := strings.ToLower(tradeMode)
+	switch {
+	case strings.Contains(mode, "disabled"):
+		return "trading is disabled for this symbol"
+	case strings.Contains(mode, "close"):
+		return "symbol is close-only"
+	case strings.Contains(mode, "long") && !orderType.IsLong():
+		return "symbol allows long positions only"
+	case strings.Contains(mode, "short") && orderType.IsLong():
+		return "symbol allows short positions only"
+	}
+	return ""
+}
+
+// roundToStep ปัดค่าให้เป็นผลคูณของ step ด้วยฟังก์ชันที่กำหนด (math.Floor, math.Round)
+func roundToStep(value, step float64, round func(float64) float64) float64 {
+	// บวก epsilon กันกรณี 0.3/0.1 = 2.9999999
+	steps := round(value/step + 1e-9)
+	return roundToDigits(steps*step, decimalPlaces(step))
+}
+
+// roundToDigits ปัดทศนิยมตามจำนวนหลัก
+func roundToDigits(value float64, digits int) float64 {
+	pow := math.Pow10(digits)
+	return math.Round(value*pow) / pow
+}
+
+// decimalPlaces จำนวนทศนิยมของ step เช่น 0.01 → 2, 0.5 → 1, 1 → 0
+func decimalPlaces(step float64) int {
+	for digits := 0; digits < 10; digits++ {
+		if math.Abs(step-roundToDigits(step, digits)) < 1e-12 {
+			return digits
+		}
+	}
+	return 10
+}
@@ -0,0 +1,22 @@
+package mt5client
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRoundToStep(t *testing.T) {
+	tests := []struct {
+		value, step, want float64
+	}{
+		{0.3, 0.1, 0.3},
+		{0.129, 0.01, 0.12},
+		{1.23456, 0.5, 1},
+		{7, 1, 7},
+	}
+	for _, tt := range tests {
+		if got := roundToStep(tt.value, tt.step, math.Floor); got != tt.want {
+			t.Errorf("roundToStep(%g, %g) = %g, want %g", tt.value, tt.step, got, tt.want)
+		}
+	}
+}
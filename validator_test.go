@@ -0,0 +1,119 @@
+package mt5client_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ditthkr/mt5client"
+	"github.com/ditthkr/mt5client/mt5test"
+)
+
+// newValidatorGateway gateway ที่ USDJPY มี 3 หลัก, tick 0.005, LotsStep 0.01, ระยะ SL/TP ขั้นต่ำ 100 points
+func newValidatorGateway(t *testing.T, tradeMode string) (*mt5test.Server, *mt5client.Client) {
+	t.Helper()
+
+	srv, client := newGateway(t, mt5client.WithOrderValidation())
+	params := mt5test.ForexSymbol("USDJPY", 3)
+	params.SymbolInfo.TickSize = 0.005
+	params.SymbolGroup.TradeMode = tradeMode
+	params.SymbolGroup.Sl = 100
+	params.SymbolGroup.Tp = 100
+	params.SymbolGroup.MaxLots = 5
+	srv.AddSymbol(params)
+	srv.SetQuote("USDJPY", 150.000, 150.020)
+	return srv, client
+}
+
+func TestOrderValidationNormalizesRequest(t *testing.T) {
+	srv, client := newValidatorGateway(t, "Full")
+
+	_, err := client.Trading.Send(mt5client.OrderRequest{
+		Symbol:     "USDJPY",
+		Type:       mt5client.OrderBuy,
+		Volume:     0.129,
+		StopLoss:   149.8123,
+		TakeProfit: 150.5071,
+	})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	sent := srv.LastQuery("/OrderSend")
+	want := map[string]string{
+		"volume":     "0.12",
+		"stoploss":   "149.810",
+		"takeprofit": "150.505",
+	}
+	for key, value := range want {
+		if got := sent.Get(key); got != value {
+			t.Errorf("%s: expected %q, got %q", key, value, got)
+		}
+	}
+
+	if _, err := client.Trading.Send(mt5client.OrderRequest{Symbol: "USDJPY", Type: mt5client.OrderSell, Volume: 0.1}); err != nil {
+		t.Fatalf("second Send failed: %v", err)
+	}
+	if n := srv.RequestCount("/SymbolParams"); n != 1 {
+		t.Errorf("expected SymbolParams to be fetched once, got %d", n)
+	}
+}
+
+func TestOrderValidationRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		tradeMode string
+		req       mt5client.OrderRequest
+		field     string
+	}{
+		{"below min lots", "Full", mt5client.OrderRequest{Type: mt5client.OrderBuy, Volume: 0.005}, "volume"},
+		{"above max lots", "Full", mt5client.OrderRequest{Type: mt5client.OrderBuy, Volume: 10}, "volume"},
+		{"stop loss too close", "Full", mt5client.OrderRequest{Type: mt5client.OrderBuy, Volume: 0.1, StopLoss: 149.95}, "stoploss"},
+		{"take profit wrong side", "Full", mt5client.OrderRequest{Type: mt5client.OrderSell, Volume: 0.1, TakeProfit: 150.5}, "takeprofit"},
+		{"pending stop too close", "Full", mt5client.OrderRequest{Type: mt5client.OrderBuyLimit, Volume: 0.1, Price: 149.5, TakeProfit: 149.55}, "takeprofit"},
+		{"trading disabled", "Disabled", mt5client.OrderRequest{Type: mt5client.OrderBuy, Volume: 0.1}, "type"},
+		{"long only", "LongOnly", mt5client.OrderRequest{Type: mt5client.OrderSellLimit, Volume: 0.1, Price: 151}, "type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newValidatorGateway(t, tt.tradeMode)
+
+			tt.req.Symbol = "USDJPY"
+			_, err := client.Trading.Send(tt.req)
+
+			var validationErr *mt5client.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("expected field %q, got %q (%v)", tt.field, validationErr.Field, err)
+			}
+			if !errors.Is(err, mt5client.ErrInvalidArgument) {
+				t.Error("expected ErrInvalidArgument")
+			}
+			if n := srv.RequestCount("/OrderSend"); n != 0 {
+				t.Errorf("expected OrderSend not to be called, got %d", n)
+			}
+		})
+	}
+}
+
+func TestOrderValidationWithoutLotsStep(t *testing.T) {
+	srv, client := newGateway(t, mt5client.WithOrderValidation())
+	params := mt5test.ForexSymbol("EURUSD", 5)
+	params.SymbolInfo.Digits = 0
+	params.SymbolInfo.TickSize = 0
+	params.SymbolGroup.LotsStep = 0
+	srv.AddSymbol(params)
+
+	if _, err := client.Trading.Send(mt5client.OrderRequest{Symbol: "EURUSD", Type: mt5client.OrderBuyLimit, Volume: 0.15, Price: 1.234567}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	sent := srv.LastQuery("/OrderSend")
+	if got := sent.Get("volume"); got != "0.15" {
+		t.Errorf("expected volume 0.15, got %q", got)
+	}
+	if got := sent.Get("price"); got != "1.23457" {
+		t.Errorf("expected price 1.23457, got %q", got)
+	}
+}
@@ -1,9 +1,12 @@
 package mt5client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	neturl "net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +24,8 @@ type WebSocketClient struct {
 	autoReconnect   bool
 	reconnectDelay  time.Duration
 	subscribedPaths map[string]func([]byte) // เก็บ paths ที่ subscribe ไว้สำหรับ reconnect
+	logger          *slog.Logger
+	observers       []WebSocketObserver
 }
 
 // EventHandlers handlers สำหรับ events ต่างๆ
@@ -41,6 +46,21 @@ type EventHandlers struct {
 	OnOhlc                func(*OhlcData)
 }
 
+// WebSocketObserver รับเหตุการณ์ระดับ stream (ใช้สำหรับ metrics/monitoring)
+// path ที่ส่งมาไม่รวม query string เช่น "/OnQuote"
+type WebSocketObserver interface {
+	OnMessage(path string)
+	OnParseError(path string, err error)
+	OnReconnect(path string)
+	OnConnectionState(path string, up bool)
+}
+
+// WebSocketFrameObserver observer ที่ต้องการข้อมูลดิบของทุก frame ด้วย (เช่น recorder)
+type WebSocketFrameObserver interface {
+	WebSocketObserver
+	OnFrame(path string, data []byte)
+}
+
 type SocketResponse struct {
 	Type string          `json:"type"`
 	Data json.RawMessage `json:"data"`
@@ -82,7 +102,89 @@ func (r *Client) NewWebSocketClient() *WebSocketClient {
 		autoReconnect:   true, // เปิด auto-reconnect โดยdefault
 		reconnectDelay:  5 * time.Second,
 		subscribedPaths: make(map[string]func([]byte)),
+		logger:          r.logger.With(slog.String("component", "websocket")),
+	}
+}
+
+// SetLogger ตั้งค่า logger (nil = ปิด log)
+func (ws *WebSocketClient) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.New(discardHandler{})
 	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.logger = logger
+}
+
+// SetObserver ตั้งค่า observer สำหรับเหตุการณ์ระดับ stream (แทนที่ observer เดิมทั้งหมด)
+func (ws *WebSocketClient) SetObserver(observer WebSocketObserver) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.observers = nil
+	if observer != nil {
+		ws.observers = append(ws.observers, observer)
+	}
+}
+
+// AddObserver เพิ่ม observer (เช่น metrics และ recorder พร้อมกัน)
+func (ws *WebSocketClient) AddObserver(observer WebSocketObserver) {
+	if observer == nil {
+		return
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.observers = append(ws.observers, observer)
+}
+
+// observe เรียก fn กับทุก observer
+func (ws *WebSocketClient) observe(fn func(WebSocketObserver)) {
+	ws.mu.RLock()
+	observers := ws.observers
+	ws.mu.RUnlock()
+
+	for _, observer := range observers {
+		fn(observer)
+	}
+}
+
+// parseError แจ้ง parse error ไปยัง observer และ OnError handler
+func (ws *WebSocketClient) parseError(path string, err error) {
+	ws.observe(func(o WebSocketObserver) { o.OnParseError(path, err) })
+
+	if handlers := ws.getHandlers(); handlers.OnError != nil {
+		handlers.OnError(err)
+	}
+}
+
+// getHandlers คืน handlers ปัจจุบัน (SetHandlers อาจถูกเรียกระหว่างที่ stream ทำงานอยู่)
+func (ws *WebSocketClient) getHandlers() *EventHandlers {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return ws.handlers
+}
+
+// doneChan คืน channel done ปัจจุบัน (Connect สร้างใหม่ทุกครั้ง)
+func (ws *WebSocketClient) doneChan() chan struct{} {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return ws.done
+}
+
+// streamPath ตัด query string ออกจาก path
+func streamPath(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// log คืน logger ปัจจุบัน
+func (ws *WebSocketClient) log() *slog.Logger {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return ws.logger
 }
 
 // SetAutoReconnect ตั้งค่า auto-reconnect
@@ -111,8 +213,8 @@ func (ws *WebSocketClient) Connect() error {
 		return fmt.Errorf("already connected")
 	}
 
-	if ws.client.token == "" {
-		return fmt.Errorf("not connected to MT5")
+	if ws.client.GetToken() == "" {
+		return fmt.Errorf("%w to MT5", ErrNotConnected)
 	}
 
 	ws.isActive = true
@@ -128,12 +230,13 @@ func (ws *WebSocketClient) Connect() error {
 
 // connectToPath เชื่อมต่อไปยัง path เฉพาะ
 func (ws *WebSocketClient) connectToPath(path string, handler func([]byte)) error {
-	if ws.client.token == "" {
-		return fmt.Errorf("not connected to MT5")
+	token := ws.client.GetToken()
+	if token == "" {
+		return fmt.Errorf("%w to MT5", ErrNotConnected)
 	}
 
 	// แปลง http:// เป็น ws://
-	wsURL := ws.client.baseURL
+	wsURL := ws.client.baseURL + ws.client.basePath
 	if len(wsURL) > 7 && wsURL[:7] == "http://" {
 		wsURL = "ws://" + wsURL[7:]
 	} else if len(wsURL) > 8 && wsURL[:8] == "https://" {
@@ -141,13 +244,38 @@ func (ws *WebSocketClient) connectToPath(path string, handler func([]byte)) erro
 	}
 
 	// สร้าง WebSocket URL: ws://host:port/OnQuote?id=token
-	url := fmt.Sprintf("%s%s?id=%s", wsURL, path, ws.client.token)
+	// (ถ้าตั้ง tokenHeader ไว้ token จะอยู่ใน header แทน)
+	url := wsURL + path
+	header := ws.client.headers.Clone()
+	if ws.client.tokenHeader != "" {
+		header.Set(ws.client.tokenHeader, token)
+	} else {
+		separator := "?"
+		if strings.Contains(path, "?") {
+			separator = "&"
+		}
+		url += separator + "id=" + neturl.QueryEscape(token)
+	}
 
-	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = ws.client.tlsConfig
+
+	conn, _, err := dialer.Dial(url, header)
 	if err != nil {
+		ws.log().Debug("websocket dial failed",
+			slog.String("path", path),
+			slog.String("url", redactURL(url)),
+			slog.Any("error", err),
+		)
 		return fmt.Errorf("failed to connect to %s: %w", path, err)
 	}
 
+	ws.log().Debug("websocket connected",
+		slog.String("path", path),
+		slog.String("url", redactURL(url)),
+	)
+	ws.observe(func(o WebSocketObserver) { o.OnConnectionState(streamPath(path), true) })
+
 	ws.mu.Lock()
 	ws.connections[path] = conn
 	ws.subscribedPaths[path] = handler // เก็บไว้สำหรับ reconnect
@@ -161,9 +289,11 @@ func (ws *WebSocketClient) connectToPath(path string, handler func([]byte)) erro
 
 // reconnectPath reconnect ไปยัง path
 func (ws *WebSocketClient) reconnectPath(path string, handler func([]byte)) {
+	done := ws.doneChan()
+	attempt := 0
 	for {
 		select {
-		case <-ws.done:
+		case <-done:
 			return
 		default:
 			ws.mu.RLock()
@@ -175,39 +305,105 @@ func (ws *WebSocketClient) reconnectPath(path string, handler func([]byte)) {
 				return
 			}
 
-			log.Printf("Reconnecting to %s in %v...", path, delay)
+			attempt++
+			ws.log().Info("reconnecting",
+				slog.String("path", path),
+				slog.Int("attempt", attempt),
+				slog.Duration("delay", delay),
+			)
 			time.Sleep(delay)
 
 			// ตรวจสอบว่า MT5 connection ยังใช้ได้อยู่หรือไม่
 			isConnected, err := ws.client.Connection.IsConnected()
 			if err != nil || !isConnected {
-				log.Printf("MT5 connection lost, attempting to re-authenticate...")
-
-				// เรียก OnReauthenticate handler ถ้ามี
-				if ws.handlers.OnReauthenticate != nil {
-					if err := ws.handlers.OnReauthenticate(); err != nil {
-						log.Printf("Failed to re-authenticate: %v", err)
+				ws.log().Warn("MT5 connection lost, attempting to re-authenticate",
+					slog.String("path", path),
+					slog.Int("attempt", attempt),
+					slog.Any("error", err),
+				)
+
+				// เรียก OnReauthenticate handler ถ้ามี (ไม่มีแต่ผูกกับ Session จะ re-login ผ่าน Session)
+				ws.mu.RLock()
+				reauthenticate := ws.handlers.OnReauthenticate
+				ws.mu.RUnlock()
+				if session := ws.client.getSession(); reauthenticate == nil && session != nil {
+					stale := ws.client.GetToken()
+					reauthenticate = func() error {
+						_, err := session.reconnect(context.Background(), stale)
+						return err
+					}
+				}
+				if reauthenticate != nil {
+					if err := reauthenticate(); err != nil {
+						ws.log().Error("re-authentication failed",
+							slog.String("path", path),
+							slog.Int("attempt", attempt),
+							slog.Any("error", err),
+						)
 						continue
 					}
-					log.Printf("✅ Re-authenticated to MT5")
+					ws.log().Info("re-authenticated to MT5", slog.String("path", path))
 				} else {
-					log.Printf("❌ No OnReauthenticate handler set, cannot re-authenticate")
+					ws.log().Error("no OnReauthenticate handler set, cannot re-authenticate",
+						slog.String("path", path),
+					)
 					continue
 				}
 			}
 
+			// Session re-login จะ resubscribe ให้แล้ว
+			ws.mu.RLock()
+			_, reconnected := ws.connections[path]
+			ws.mu.RUnlock()
+			if reconnected {
+				return
+			}
+
 			err = ws.connectToPath(path, handler)
 			if err != nil {
-				log.Printf("Failed to reconnect to %s: %v", path, err)
+				ws.log().Error("reconnect failed",
+					slog.String("path", path),
+					slog.Int("attempt", attempt),
+					slog.Any("error", err),
+				)
 				continue
 			}
 
-			log.Printf("✅ Reconnected to %s", path)
+			ws.log().Info("reconnected", slog.String("path", path), slog.Int("attempt", attempt))
+			ws.observe(func(o WebSocketObserver) { o.OnReconnect(streamPath(path)) })
 			return
 		}
 	}
 }
 
+// resubscribe เชื่อมต่อทุก stream ที่ subscribe ไว้ใหม่ด้วย token ปัจจุบัน
+func (ws *WebSocketClient) resubscribe() {
+	ws.mu.Lock()
+	if !ws.isActive {
+		ws.mu.Unlock()
+		return
+	}
+	paths := make(map[string]func([]byte), len(ws.subscribedPaths))
+	for path, handler := range ws.subscribedPaths {
+		paths[path] = handler
+		if conn, ok := ws.connections[path]; ok {
+			delete(ws.connections, path)
+			conn.Close()
+		}
+	}
+	autoReconnect := ws.autoReconnect
+	ws.mu.Unlock()
+
+	for path, handler := range paths {
+		if err := ws.connectToPath(path, handler); err != nil {
+			ws.log().Error("resubscribe failed", slog.String("path", path), slog.Any("error", err))
+			if autoReconnect {
+				go ws.reconnectPath(path, handler)
+			}
+		}
+	}
+}
+
 // SubscribeQuote subscribe รับ quote real-time
 func (ws *WebSocketClient) SubscribeQuote() error {
 	return ws.connectToPath("/OnQuote", ws.handleQuoteMessage)
@@ -261,19 +457,26 @@ func (ws *WebSocketClient) SubscribeOhlcWS() error {
 
 // readMessages อ่านข้อความจาก WebSocket
 func (ws *WebSocketClient) readMessages(path string, conn *websocket.Conn, handler func([]byte)) {
+	done := ws.doneChan()
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("WebSocket %s panic recovered: %v", path, r)
+			ws.log().Error("panic recovered in message handler",
+				slog.String("path", path),
+				slog.Any("panic", r),
+			)
 		}
 		conn.Close()
 		ws.mu.Lock()
-		delete(ws.connections, path)
+		// connection อาจถูกแทนที่แล้ว (เช่น resubscribe หลัง token เปลี่ยน) ไม่ต้อง reconnect ซ้ำ
+		replaced := ws.connections[path] != conn
+		if !replaced {
+			delete(ws.connections, path)
+		}
+		shouldReconnect := ws.autoReconnect && ws.isActive && !replaced
 		ws.mu.Unlock()
+		ws.observe(func(o WebSocketObserver) { o.OnConnectionState(streamPath(path), false) })
 
 		// Auto-reconnect ถ้าเปิดใช้งาน
-		ws.mu.RLock()
-		shouldReconnect := ws.autoReconnect && ws.isActive
-		ws.mu.RUnlock()
 
 		if shouldReconnect {
 			go ws.reconnectPath(path, handler)
@@ -282,17 +485,25 @@ func (ws *WebSocketClient) readMessages(path string, conn *websocket.Conn, handl
 
 	for {
 		select {
-		case <-ws.done:
+		case <-done:
 			return
 		default:
 			_, message, err := conn.ReadMessage()
 			if err != nil {
-				if ws.handlers.OnError != nil {
-					ws.handlers.OnError(fmt.Errorf("%s error: %v", path, err))
+				ws.log().Warn("websocket read failed", slog.String("path", path), slog.Any("error", err))
+				if handlers := ws.getHandlers(); handlers.OnError != nil {
+					handlers.OnError(fmt.Errorf("%s error: %v", path, err))
 				}
 				return
 			}
 
+			ws.observe(func(o WebSocketObserver) {
+				if fo, ok := o.(WebSocketFrameObserver); ok {
+					fo.OnFrame(streamPath(path), message)
+				}
+				o.OnMessage(streamPath(path))
+			})
+
 			// ประมวลผลข้อความ
 			handler(message)
 		}
@@ -301,7 +512,8 @@ func (ws *WebSocketClient) readMessages(path string, conn *websocket.Conn, handl
 
 // Message handlers
 func (ws *WebSocketClient) handleQuoteMessage(data []byte) {
-	if ws.handlers.OnQuote == nil {
+	handlers := ws.getHandlers()
+	if handlers.OnQuote == nil {
 		return
 	}
 
@@ -311,9 +523,7 @@ func (ws *WebSocketClient) handleQuoteMessage(data []byte) {
 		Data json.RawMessage `json:"data"`
 	}
 	if err := json.Unmarshal(data, &genericResp); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse quote response: %v", err))
-		}
+		ws.parseError("/OnQuote", fmt.Errorf("failed to parse quote response: %v", err))
 		return
 	}
 
@@ -327,67 +537,60 @@ func (ws *WebSocketClient) handleQuoteMessage(data []byte) {
 	// แปลงเป็น Quote object
 	var quote Quote
 	if err := json.Unmarshal(genericResp.Data, &quote); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse quote data: %v", err))
-		}
+		ws.parseError("/OnQuote", fmt.Errorf("failed to parse quote data: %v", err))
 		return
 	}
 
-	ws.handlers.OnQuote(&quote)
+	handlers.OnQuote(&quote)
 }
 
 func (ws *WebSocketClient) handleTickValueMessage(data []byte) {
-	if ws.handlers.OnTickValue == nil {
+	handlers := ws.getHandlers()
+	if handlers.OnTickValue == nil {
 		return
 	}
 
 	var event TickValueEvent
 	if err := json.Unmarshal(data, &event); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse tick value: %v", err))
-		}
+		ws.parseError("/OnTickValue", fmt.Errorf("failed to parse tick value: %v", err))
 		return
 	}
 
-	ws.handlers.OnTickValue(&event)
+	handlers.OnTickValue(&event)
 }
 
 func (ws *WebSocketClient) handleOrderUpdateMessage(data []byte) {
-	if ws.handlers.OnOrderUpdate == nil {
+	handlers := ws.getHandlers()
+	if handlers.OnOrderUpdate == nil {
 		return
 	}
 
 	var raw SocketResponse
 	if err := json.Unmarshal(data, &raw); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse raw order update: %v", err))
-		}
+		ws.parseError("/OnOrderUpdate", fmt.Errorf("failed to parse raw order update: %v", err))
 		return
 	}
 	switch raw.Type {
 	case "OrderUpdate":
 		var event OrderUpdateEvent
 		if err := json.Unmarshal(raw.Data, &event); err != nil {
-			if ws.handlers.OnError != nil {
-				ws.handlers.OnError(fmt.Errorf("failed to parse raw order update: %v", err))
-			}
+			ws.parseError("/OnOrderUpdate", fmt.Errorf("failed to parse raw order update: %v", err))
 			return
 		}
-		ws.handlers.OnOrderUpdate(&event)
+		handlers.OnOrderUpdate(&event)
 	}
 
 }
 
 func (ws *WebSocketClient) handleOrderProfitMessage(data []byte) {
-	if ws.handlers.OnOrderProfit == nil {
+	handlers := ws.getHandlers()
+	if handlers.OnOrderProfit == nil {
 		return
 	}
 
 	var raw SocketResponse
 	if err := json.Unmarshal(data, &raw); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse raw order profit: %v", err))
-		}
+		ws.parseError("/OnOrderProfit", fmt.Errorf("failed to parse raw order profit: %v", err))
 		return
 	}
 
@@ -395,110 +598,102 @@ func (ws *WebSocketClient) handleOrderProfitMessage(data []byte) {
 	case "OrderProfit":
 		var event OrderProfitEvent
 		if err := json.Unmarshal(raw.Data, &event); err != nil {
-			if ws.handlers.OnError != nil {
-				ws.handlers.OnError(fmt.Errorf("failed to parse order profit: %v", err))
-			}
+			ws.parseError("/OnOrderProfit", fmt.Errorf("failed to parse order profit: %v", err))
 			return
 		}
-		ws.handlers.OnOrderProfit(&event)
+		handlers.OnOrderProfit(&event)
 	}
 
 }
 
 func (ws *WebSocketClient) handleMarketWatchMessage(data []byte) {
-	if ws.handlers.OnMarketWatch == nil {
+	handlers := ws.getHandlers()
+	if handlers.OnMarketWatch == nil {
 		return
 	}
 
 	var mw MarketWatch
 	if err := json.Unmarshal(data, &mw); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse market watch: %v", err))
-		}
+		ws.parseError("/OnMarketWatch", fmt.Errorf("failed to parse market watch: %v", err))
 		return
 	}
 
-	ws.handlers.OnMarketWatch(&mw)
+	handlers.OnMarketWatch(&mw)
 }
 
 func (ws *WebSocketClient) handleTickHistoryMessage(data []byte) {
-	if ws.handlers.OnTickHistory == nil {
+	handlers := ws.getHandlers()
+	if handlers.OnTickHistory == nil {
 		return
 	}
 
 	var event TickHistoryEvent
 	if err := json.Unmarshal(data, &event); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse tick history: %v", err))
-		}
+		ws.parseError("/OnTickHistory", fmt.Errorf("failed to parse tick history: %v", err))
 		return
 	}
 
-	ws.handlers.OnTickHistory(&event)
+	handlers.OnTickHistory(&event)
 }
 
 func (ws *WebSocketClient) handleMailMessage(data []byte) {
-	if ws.handlers.OnMail == nil {
+	handlers := ws.getHandlers()
+	if handlers.OnMail == nil {
 		return
 	}
 
 	var mail Mail
 	if err := json.Unmarshal(data, &mail); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse mail: %v", err))
-		}
+		ws.parseError("/OnMail", fmt.Errorf("failed to parse mail: %v", err))
 		return
 	}
 
-	ws.handlers.OnMail(&mail)
+	handlers.OnMail(&mail)
 }
 
 func (ws *WebSocketClient) handleOpenedOrdersTicketsMessage(data []byte) {
-	if ws.handlers.OnOpenedOrdersTickets == nil {
+	handlers := ws.getHandlers()
+	if handlers.OnOpenedOrdersTickets == nil {
 		return
 	}
 
 	var tickets []int64
 	if err := json.Unmarshal(data, &tickets); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse opened orders tickets: %v", err))
-		}
+		ws.parseError("/OnOpenedOrdersTickets", fmt.Errorf("failed to parse opened orders tickets: %v", err))
 		return
 	}
 
-	ws.handlers.OnOpenedOrdersTickets(tickets)
+	handlers.OnOpenedOrdersTickets(tickets)
 }
 
 func (ws *WebSocketClient) handleOrderBookMessage(data []byte) {
-	if ws.handlers.OnOrderBook == nil {
+	handlers := ws.getHandlers()
+	if handlers.OnOrderBook == nil {
 		return
 	}
 
 	var book OrderBook
 	if err := json.Unmarshal(data, &book); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse order book: %v", err))
-		}
+		ws.parseError("/OnOrderBook", fmt.Errorf("failed to parse order book: %v", err))
 		return
 	}
 
-	ws.handlers.OnOrderBook(&book)
+	handlers.OnOrderBook(&book)
 }
 
 func (ws *WebSocketClient) handleOhlcMessage(data []byte) {
-	if ws.handlers.OnOhlc == nil {
+	handlers := ws.getHandlers()
+	if handlers.OnOhlc == nil {
 		return
 	}
 
 	var ohlc OhlcData
 	if err := json.Unmarshal(data, &ohlc); err != nil {
-		if ws.handlers.OnError != nil {
-			ws.handlers.OnError(fmt.Errorf("failed to parse ohlc: %v", err))
-		}
+		ws.parseError("/OnOhlc", fmt.Errorf("failed to parse ohlc: %v", err))
 		return
 	}
 
-	ws.handlers.OnOhlc(&ohlc)
+	handlers.OnOhlc(&ohlc)
 }
 
 // Disconnect ตัดการเชื่อมต่อทั้งหมด
@@ -539,12 +734,13 @@ func (ws *WebSocketClient) IsConnected() bool {
 
 // KeepAlive ส่ง ping เป็นระยะ (สำหรับ connections ทั้งหมด)
 func (ws *WebSocketClient) KeepAlive(interval time.Duration) {
+	done := ws.doneChan()
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ws.done:
+		case <-done:
 			return
 		case <-ticker.C:
 			ws.mu.RLock()